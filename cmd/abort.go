@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/mona-actions/gh-migration-monitor/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	abortRepositories []string
+	abortMigrationIDs []string
+	abortStatus       string
+	abortSearch       string
+	abortDryRun       bool
+	abortYes          bool
+)
+
+// abortCmd aborts queued and in-progress GEI migrations
+var abortCmd = &cobra.Command{
+	Use:   "abort",
+	Short: "Abort queued and in-progress migrations",
+	Long: `Abort queued and in-progress GEI migrations, for example a batch that was started
+against the wrong organization.
+
+Select migrations by migration ID or repository (the most recent migration of the
+repository), or with the same status filter and search term as the list command.
+Only queued and in-progress migrations are aborted. The migrations to abort are
+listed and must be confirmed unless --yes is given; --dry-run only lists them.
+Aborting is not supported for legacy migrations.`,
+	Example: `  migration-monitor abort --organization myorg --migration-id RM_kgDaACQ...
+  migration-monitor abort --organization myorg --repository frontend --repository backend
+  migration-monitor abort --organization wrong-org --status queued --dry-run
+  migration-monitor abort --organization wrong-org --search api --yes`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runAbort,
+}
+
+func init() {
+	rootCmd.AddCommand(abortCmd)
+
+	abortCmd.Flags().StringSliceVar(&abortMigrationIDs, "migration-id", nil, "Migration ID to abort (repeatable)")
+	abortCmd.Flags().StringSliceVarP(&abortRepositories, "repository", "r", nil, "Repository name, or org/repo, whose latest migration to abort (repeatable)")
+	abortCmd.Flags().StringVarP(&abortStatus, "status", "s", "", "Abort every migration with this status: all, queued or in-progress")
+	abortCmd.Flags().StringVar(&abortSearch, "search", "", "Abort every migration whose repository name contains this term")
+	abortCmd.Flags().BoolVar(&abortDryRun, "dry-run", false, "Only list the migrations that would be aborted")
+	abortCmd.Flags().BoolVarP(&abortYes, "yes", "y", false, "Do not ask for confirmation")
+}
+
+func runAbort(cmd *cobra.Command, args []string) error {
+	target := services.WaitTarget{
+		Repositories: abortRepositories,
+		MigrationIDs: abortMigrationIDs,
+	}
+	useFilter := abortStatus != "" || abortSearch != ""
+	if target.IsEmpty() == !useFilter {
+		return fmt.Errorf("select migrations with --migration-id or --repository, or with --status and --search")
+	}
+
+	filter := ui.FilterAll
+	if abortStatus != "" {
+		var err error
+		if filter, err = ui.ParseFilterOption(abortStatus); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Migration.IsLegacy {
+		return fmt.Errorf("aborting is only supported for GEI migrations")
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+
+	orgs, err := newOrganizationResolver(cfg, migrationService).Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary, err := services.MergeResults(migrationService.ListOrganizationsMigrations(ctx, orgs, models.ListOptions{}, false))
+	if err != nil {
+		return err
+	}
+
+	// Select the migrations; those named explicitly are reported when they cannot be aborted
+	var abortable []models.Migration
+	if useFilter {
+		for _, migration := range ui.FilterMigrations(summary.All(), filter, abortSearch) {
+			if services.CheckAbortable(migration) == nil {
+				abortable = append(abortable, migration)
+			}
+		}
+	} else {
+		selected, missing := services.SelectMigrations(summary.All(), target)
+		for _, name := range missing {
+			fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: no migration found\n", name)
+		}
+		for _, migration := range selected {
+			if err := services.CheckAbortable(migration); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: %v\n", migration.FullName(), err)
+				continue
+			}
+			abortable = append(abortable, migration)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if len(abortable) == 0 {
+		fmt.Fprintln(out, "No queued or in-progress migrations to abort")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Migrations to abort: %d\n", len(abortable))
+	for _, migration := range abortable {
+		fmt.Fprintf(out, "  %s (%s) %s\n", migration.FullName(), migration.ID, migration.State)
+	}
+
+	if abortDryRun {
+		fmt.Fprintln(out, "Dry run, no migrations were aborted")
+		return nil
+	}
+
+	if !abortYes {
+		confirmed, err := confirm(cmd.InOrStdin(), out, fmt.Sprintf("Abort %d migrations?", len(abortable)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("abort cancelled")
+		}
+	}
+
+	results := migrationService.AbortMigrations(ctx, abortable, false)
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(out, "FAILED %s: %v\n", result.Migration.FullName(), result.Err)
+			continue
+		}
+		fmt.Fprintf(out, "ABORTED %s (%s)\n", result.Migration.FullName(), result.Migration.ID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d aborts failed", failed, len(results))
+	}
+	return nil
+}
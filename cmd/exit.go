@@ -0,0 +1,29 @@
+package cmd
+
+import "fmt"
+
+// Process exit codes used by commands that report an outcome to CI pipelines
+const (
+	exitCodeError            = 1
+	exitCodeMigrationsFailed = 2
+	exitCodeTimeout          = 3
+)
+
+// exitError carries a specific process exit code out of a command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps an error so that Execute exits with the given code
+func withExitCode(code int, format string, args ...interface{}) error {
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
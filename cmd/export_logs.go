@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/logs"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/mona-actions/gh-migration-monitor/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportLogsOutput      string
+	exportLogsStatus      string
+	exportLogsSearch      string
+	exportLogsSince       string
+	exportLogsUntil       string
+	exportLogsConcurrency int
+	exportLogsForce       bool
+)
+
+// exportLogsCmd downloads the logs of every selected migration into a directory
+var exportLogsCmd = &cobra.Command{
+	Use:   "export-logs",
+	Short: "Download the logs of many migrations into a directory",
+	Long: `Download the migration log of every migration in the organizations, optionally
+filtered by status, repository name or creation time, into a directory for auditing
+a migration wave.
+
+Each log is written to <output>/<organization>/<repository>/<migration-id>.log and
+index.json at the root of the directory lists every migration with its state and
+the outcome of its download. Running the command again resumes an interrupted
+export: logs of finished migrations that were already downloaded are kept, while
+failed downloads and logs of running migrations are downloaded again.
+
+--since and --until take a date (2006-01-02), a timestamp (RFC 3339) or a duration
+before now such as 48h. Migration logs are not available for legacy migrations.`,
+	Example: `  migration-monitor export-logs --organization myorg --output wave3-logs
+  migration-monitor export-logs --organization myorg --status failed --since 48h
+  migration-monitor export-logs --organization myorg --since 2024-05-01 --until 2024-05-08 --concurrency 8`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runExportLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(exportLogsCmd)
+
+	exportLogsCmd.Flags().StringVar(&exportLogsOutput, "output", "migration-logs", "Directory to write the logs and index.json to")
+	exportLogsCmd.Flags().StringVarP(&exportLogsStatus, "status", "s", "all", "Only export migrations with this status: all, queued, in-progress, succeeded or failed")
+	exportLogsCmd.Flags().StringVar(&exportLogsSearch, "search", "", "Only export migrations whose repository name contains this term")
+	exportLogsCmd.Flags().StringVar(&exportLogsSince, "since", "", "Only export migrations created at or after this date, timestamp or duration ago")
+	exportLogsCmd.Flags().StringVar(&exportLogsUntil, "until", "", "Only export migrations created before this date, timestamp or duration ago")
+	exportLogsCmd.Flags().IntVar(&exportLogsConcurrency, "concurrency", api.DefaultConcurrency, "Number of logs downloaded at once")
+	exportLogsCmd.Flags().BoolVar(&exportLogsForce, "force", false, "Download every log again, even if it was exported before")
+}
+
+func runExportLogs(cmd *cobra.Command, args []string) error {
+	filter, err := ui.ParseFilterOption(exportLogsStatus)
+	if err != nil {
+		return err
+	}
+
+	window, err := services.ParseTimeWindow(exportLogsSince, exportLogsUntil)
+	if err != nil {
+		return err
+	}
+	if exportLogsConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Migration.IsLegacy {
+		return fmt.Errorf("migration logs are only available for GEI migrations")
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Interrupting the export still writes the index of the logs downloaded so far
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	orgs, err := resolveOrganizations(ctx, newOrganizationResolver(cfg, migrationService))
+	if err != nil {
+		return err
+	}
+
+	// Only list the migrations in the selected state and time window
+	opts := window.Apply(models.ListOptions{State: filter.ListState()}, time.Now())
+	summary, err := services.MergeResults(migrationService.ListOrganizationsMigrations(ctx, orgs, opts, false))
+	if err != nil {
+		return err
+	}
+
+	migrations := ui.FilterMigrations(summary.All(), filter, exportLogsSearch)
+
+	out := cmd.OutOrStdout()
+	errOut := cmd.ErrOrStderr()
+	if len(migrations) == 0 {
+		fmt.Fprintln(out, "No migrations to export")
+		return nil
+	}
+
+	fmt.Fprintf(errOut, "Exporting the logs of %d migrations to %s\n", len(migrations), exportLogsOutput)
+
+	completed := 0
+	index, err := logs.Export(ctx, migrationService, exportLogsOutput, migrations, logs.ExportOptions{
+		Concurrency: exportLogsConcurrency,
+		Force:       exportLogsForce,
+		Progress: func(entry logs.IndexEntry) {
+			completed++
+			line := fmt.Sprintf("[%d/%d] %s/%s (%s): %s", completed, len(migrations), entry.Organization, entry.RepositoryName, entry.MigrationID, entry.Status)
+			if entry.Error != "" {
+				line += ": " + entry.Error
+			}
+			fmt.Fprintln(errOut, line)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Exported %d migration logs to %s: %d downloaded, %d already exported, %d without a log, %d failed\n",
+		len(index.Entries), exportLogsOutput,
+		index.Count(logs.ExportDownloaded), index.Count(logs.ExportSkipped),
+		index.Count(logs.ExportNoLog), index.Count(logs.ExportFailed))
+
+	if failed := index.Count(logs.ExportFailed); failed > 0 {
+		return fmt.Errorf("%d of %d logs could not be downloaded, run the command again to resume", failed, len(index.Entries))
+	}
+	return nil
+}
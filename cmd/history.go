@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/config"
+	"github.com/mona-actions/gh-migration-monitor/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyRepository  string
+	historyMigrationID string
+	historySince       time.Duration
+	historyFormat      string
+)
+
+// historyCmd reports the migration state transitions recorded across sessions
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded migration state transitions",
+	Long: `Show the migration state transitions recorded by the dashboard and the wait command.
+
+Every refresh compares the migrations with the last recorded state and appends any
+changes to ~/.gh-migration-monitor/history.jsonl (configurable via history.path). This
+command reports those transitions along with how long each migration spent in each
+state, which is useful for auditing a migration wave after the fact.`,
+	Example: `  migration-monitor history --organization myorg
+  migration-monitor history --repository frontend
+  migration-monitor history --since 72h --format json`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVarP(&historyRepository, "repository", "r", "", "Only show transitions for this repository")
+	historyCmd.Flags().StringVar(&historyMigrationID, "migration-id", "", "Only show transitions for this migration ID")
+	historyCmd.Flags().DurationVar(&historySince, "since", 0, "Only show transitions observed within this duration, e.g. 48h")
+	historyCmd.Flags().StringVarP(&historyFormat, "format", "f", "table", "Output format: table or json")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	// The history file can be read without a token, so the full validation is skipped
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	applyOrganizationFlag(cfg)
+
+	path, err := historyPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open migration history: %w", err)
+	}
+	defer store.Close()
+
+	filter := history.Filter{
+		Organizations:  cfg.OrganizationNames(),
+		MigrationID:    historyMigrationID,
+		RepositoryName: historyRepository,
+	}
+	if historySince > 0 {
+		filter.Since = time.Now().Add(-historySince)
+	}
+
+	durations := history.Durations(store.Transitions(filter), time.Now())
+
+	switch historyFormat {
+	case "json":
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newHistoryEntries(durations))
+	case "table", "":
+		return writeHistoryTable(cmd.OutOrStdout(), durations)
+	default:
+		return fmt.Errorf("invalid output format %q (valid: table, json)", historyFormat)
+	}
+}
+
+// historyEntry is the JSON representation of a recorded transition
+type historyEntry struct {
+	history.Transition
+	DurationSeconds int64 `json:"duration_seconds"`
+	Ongoing         bool  `json:"ongoing"`
+}
+
+// newHistoryEntries converts state durations into their JSON representation
+func newHistoryEntries(durations []history.StateDuration) []historyEntry {
+	entries := make([]historyEntry, 0, len(durations))
+	for _, d := range durations {
+		entries = append(entries, historyEntry{
+			Transition:      d.Transition,
+			DurationSeconds: int64(d.Duration.Seconds()),
+			Ongoing:         d.Ongoing,
+		})
+	}
+	return entries
+}
+
+// writeHistoryTable writes state durations as an aligned plain-text table
+func writeHistoryTable(w io.Writer, durations []history.StateDuration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "OBSERVED AT\tORGANIZATION\tREPOSITORY NAME\tMIGRATION ID\tFROM\tTO\tTIME IN STATE")
+	for _, d := range durations {
+		from := string(d.FromState)
+		if d.IsFirstObservation() {
+			from = "-"
+		}
+
+		timeInState := "-"
+		if d.Duration > 0 {
+			timeInState = d.Duration.Truncate(time.Second).String()
+			if d.Ongoing {
+				timeInState += " (ongoing)"
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.ObservedAt.Local().Format("2006-01-02 15:04:05"),
+			d.Organization,
+			d.RepositoryName,
+			d.MigrationID,
+			from,
+			d.ToState,
+			timeInState,
+		)
+	}
+
+	return tw.Flush()
+}
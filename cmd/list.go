@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/mona-actions/gh-migration-monitor/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listFormat string
+	listStatus string
+	listSearch string
+	listRepo   string
+	listSince  string
+	listUntil  string
+	listQuiet  bool
+	listWide   bool
+)
+
+// listCmd prints migrations once without starting the dashboard
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List migrations once and print them",
+	Long: `List the migrations for one or more organizations once and print them in the selected format.
+
+The output can be piped into tools such as jq or imported into spreadsheets. The same
+status filters and search term available in the dashboard can be applied.
+
+With --manifest the output lists every repository in the manifest with its migration,
+including repositories that have not started yet, followed by migrations of
+repositories that are not in the manifest.`,
+	Example: `  migration-monitor list --organization myorg --format json | jq '.failed'
+  migration-monitor list --organization myorg --status failed --format csv > failed.csv
+  migration-monitor list --organization myorg --search api --quiet
+  migration-monitor list --organization myorg --repository frontend --format json
+  migration-monitor list --organization myorg --since 48h --status failed
+  migration-monitor list --organization org-a,org-b --status failed
+  migration-monitor list --enterprise my-enterprise --status in-progress
+  migration-monitor list --organization myorg --manifest wave3.csv --status not-started`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runList,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", "", "Output format: table, json, csv or yaml (can also be set via GHMM_OUTPUT_FORMAT)")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "all", "Only show migrations with this status: all, not-started, queued, in-progress, succeeded or failed")
+	listCmd.Flags().StringVar(&listSearch, "search", "", "Only show migrations whose repository name contains this term")
+	listCmd.Flags().StringVarP(&listRepo, "repository", "r", "", "Only show migrations of the repository with exactly this name")
+	listCmd.Flags().StringVar(&listSince, "since", "", "Only show migrations created at or after this date (2006-01-02), RFC 3339 timestamp or duration ago such as 48h")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "Only show migrations created before this date, timestamp or duration ago")
+	listCmd.Flags().BoolVarP(&listWide, "wide", "w", false, "Include source and warning columns in table output")
+	listCmd.Flags().BoolVarP(&listQuiet, "quiet", "q", false, "Only print migration IDs (can also be set via GHMM_OUTPUT_QUIET)")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Override output settings with command line flags
+	if listFormat != "" {
+		cfg.Output.Format = listFormat
+	}
+	if listQuiet {
+		cfg.Output.Quiet = listQuiet
+	}
+
+	format, err := ui.ParseOutputFormat(cfg.Output.Format)
+	if err != nil {
+		return err
+	}
+
+	filter, err := ui.ParseFilterOption(listStatus)
+	if err != nil {
+		return err
+	}
+
+	window, err := services.ParseTimeWindow(listSince, listUntil)
+	if err != nil {
+		return err
+	}
+
+	waveManifest, err := loadManifest(cfg)
+	if err != nil {
+		return err
+	}
+	if waveManifest != nil && listRepo != "" {
+		return fmt.Errorf("--repository cannot be combined with a manifest")
+	}
+	if waveManifest != nil && !window.IsZero() {
+		return fmt.Errorf("--since and --until cannot be combined with a manifest")
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	orgs, err := resolveOrganizations(ctx, newOrganizationResolver(cfg, migrationService))
+	if err != nil {
+		return err
+	}
+
+	// Let GitHub filter GEI migrations by state and repository. Reconciling with a
+	// manifest needs every migration, and legacy states are matched by FilterMigrations.
+	opts := window.Apply(models.ListOptions{RepositoryName: listRepo}, time.Now())
+	if waveManifest == nil && !cfg.Migration.IsLegacy {
+		opts.State = filter.ListState()
+	}
+
+	results := migrationService.ListOrganizationsMigrations(ctx, orgs, opts, cfg.Migration.IsLegacy)
+	summary, err := services.MergeResults(results)
+	if err != nil {
+		return err
+	}
+
+	if waveManifest != nil {
+		rec := waveManifest.Reconcile(summary.All())
+		rows := ui.FilterRows(rec.Rows, filter, listSearch)
+
+		if cfg.Output.Quiet {
+			return ui.WriteRowIDs(cmd.OutOrStdout(), rows)
+		}
+		if err := ui.WriteReconciliation(cmd.OutOrStdout(), rec, rows, format); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+
+	filtered := models.NewMigrationSummary(ui.FilterMigrations(summary.All(), filter, listSearch))
+
+	if cfg.Output.Quiet {
+		return ui.WriteIDs(cmd.OutOrStdout(), filtered)
+	}
+
+	if err := ui.WriteSummary(cmd.OutOrStdout(), filtered, format, listWide); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsPrint   bool
+	logsRefresh bool
+)
+
+// logsCmd downloads and shows the log of a single migration
+var logsCmd = &cobra.Command{
+	Use:   "logs <migration-id>",
+	Short: "Download and view a migration log",
+	Long: `Download the log of a GEI migration and show it in a scrollable, searchable viewer
+with warnings and errors highlighted. When the output is not a terminal, or with
+--print, the log is written to standard output instead.
+
+Logs of finished migrations are cached under ~/.gh-migration-monitor/logs and read
+from there afterwards; --refresh downloads them again. An organization is not
+required. Migration logs are not available for legacy migrations.`,
+	Example: `  migration-monitor logs RM_kgDaACQ...
+  migration-monitor logs RM_kgDaACQ... --print | grep -i warn
+  migration-monitor logs RM_kgDaACQ... --refresh`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolVarP(&logsPrint, "print", "p", false, "Write the log to standard output instead of opening the viewer")
+	logsCmd.Flags().BoolVar(&logsRefresh, "refresh", false, "Download the log again even if it is cached")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Migration.IsLegacy {
+		return fmt.Errorf("migration logs are only available for GEI migrations")
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	cache, err := newLogCache(cfg, migrationService)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+
+	migration, err := migrationService.GetMigration(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	log, err := cache.Load(ctx, migration, logsRefresh)
+	if err != nil {
+		return err
+	}
+
+	if logsPrint || !isTerminal(os.Stdout) {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), log)
+		return err
+	}
+
+	title := fmt.Sprintf("Migration Log - %s (%s, %s)", migration.RepositoryName, migration.ID, migration.State)
+	return ui.RunLogViewer(title, log, func() (string, error) {
+		reloadCtx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+		defer cancel()
+
+		return cache.Load(reloadCtx, migration, true)
+	})
+}
+
+// isTerminal returns true if the file is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
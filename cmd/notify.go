@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/config"
+	"github.com/mona-actions/gh-migration-monitor/internal/notify"
+)
+
+// newNotificationDispatcher builds a dispatcher for the notifiers enabled in the
+// configuration. Bell and desktop notifications are written to terminal, which must
+// not interleave them with the drawing of the dashboard.
+func newNotificationDispatcher(cfg *config.Config, terminal io.Writer, onError func(error)) (*notify.Dispatcher, error) {
+	dispatcher := notify.NewDispatcher(onError)
+
+	// Terminal and command notifications only fire for finished migrations
+	if cfg.Notifications.Bell {
+		bell := notify.NewBellNotifier()
+		bell.Writer = terminal
+		dispatcher.Add(bell, notify.FilterCompleted)
+	}
+
+	if cfg.Notifications.Desktop != "" {
+		desktop, err := notify.NewDesktopNotifier(cfg.Notifications.Desktop)
+		if err != nil {
+			return nil, err
+		}
+		desktop.Writer = terminal
+		dispatcher.Add(desktop, notify.FilterCompleted)
+	}
+
+	if cfg.Notifications.Command != "" {
+		dispatcher.Add(notify.NewCommandNotifier(cfg.Notifications.Command), notify.FilterCompleted)
+	}
+
+	for i, webhook := range cfg.Notifications.Webhooks {
+		notifier, err := notify.NewWebhookNotifier(webhook.URL, webhook.Secret, webhook.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook %d: %w", i+1, err)
+		}
+
+		filters, err := notify.ParseEventFilters(webhook.Events)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook %d: %w", i+1, err)
+		}
+
+		dispatcher.Add(notifier, filters...)
+	}
+
+	return dispatcher, nil
+}
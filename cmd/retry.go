@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/config"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	retryRepositories []string
+	retryMigrationIDs []string
+	retryAllFailed    bool
+	retryDryRun       bool
+	retryYes          bool
+	sourceToken       string
+)
+
+// retryCmd re-issues failed GEI migrations
+var retryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Retry failed migrations",
+	Long: `Start a new GEI migration for each selected failed migration, using the original
+migration source, source URL and target repository name.
+
+Select migrations by repository (the most recent migration of the repository), by
+migration ID, or every repository whose most recent migration failed with --all-failed.
+Repositories that already have a newer migration are skipped. The migrations to
+retry are listed and must be confirmed unless --yes is given; --dry-run only lists them.
+
+The source token must be able to read the source repositories. The GitHub token is
+used when no source token is given. Retrying is not supported for legacy migrations.`,
+	Example: `  migration-monitor retry --organization myorg --repository frontend --dry-run
+  migration-monitor retry --organization myorg --migration-id RM_kgDaACQ... --source-token $GH_SOURCE_PAT
+  migration-monitor retry --organization myorg --all-failed --yes`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runRetry,
+}
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+
+	retryCmd.Flags().StringSliceVarP(&retryRepositories, "repository", "r", nil, "Repository name, or org/repo, whose latest migration to retry (repeatable)")
+	retryCmd.Flags().StringSliceVar(&retryMigrationIDs, "migration-id", nil, "Failed migration ID to retry (repeatable)")
+	retryCmd.Flags().BoolVar(&retryAllFailed, "all-failed", false, "Retry every repository whose most recent migration failed")
+	retryCmd.Flags().BoolVar(&retryDryRun, "dry-run", false, "Only list the migrations that would be retried")
+	retryCmd.Flags().BoolVarP(&retryYes, "yes", "y", false, "Do not ask for confirmation")
+	retryCmd.Flags().StringVar(&sourceToken, "source-token", "", "Token with access to the source repositories (can also be set via GHMM_MIGRATION_SOURCE_TOKEN)")
+}
+
+func runRetry(cmd *cobra.Command, args []string) error {
+	target := services.WaitTarget{
+		Repositories: retryRepositories,
+		MigrationIDs: retryMigrationIDs,
+	}
+	if target.IsEmpty() == !retryAllFailed {
+		return fmt.Errorf("select migrations with --repository or --migration-id, or use --all-failed")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	applySourceTokenFlag(cfg)
+
+	if cfg.Migration.IsLegacy {
+		return fmt.Errorf("retrying is only supported for GEI migrations")
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+
+	orgs, err := newOrganizationResolver(cfg, migrationService).Resolve(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary, err := services.MergeResults(migrationService.ListOrganizationsMigrations(ctx, orgs, models.ListOptions{}, false))
+	if err != nil {
+		return err
+	}
+	all := summary.All()
+
+	// Select the migrations and drop those that cannot be retried
+	var selected []models.Migration
+	if retryAllFailed {
+		selected = services.LatestFailed(all)
+	} else {
+		var missing []string
+		selected, missing = services.SelectMigrations(all, target)
+		for _, name := range missing {
+			fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: no migration found\n", name)
+		}
+	}
+
+	var retryable []models.Migration
+	for _, migration := range selected {
+		if err := services.CheckRetryable(migration, all); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: %v\n", migration.FullName(), err)
+			continue
+		}
+		retryable = append(retryable, migration)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(retryable) == 0 {
+		fmt.Fprintln(out, "No failed migrations to retry")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Failed migrations to retry: %d\n", len(retryable))
+	for _, migration := range retryable {
+		fmt.Fprintf(out, "  %s (%s) from %s: %s\n", migration.FullName(), migration.ID, migration.SourceURL, firstLine(migration.FailureReason))
+	}
+
+	if retryDryRun {
+		fmt.Fprintln(out, "Dry run, no migrations were started")
+		return nil
+	}
+
+	if !retryYes {
+		confirmed, err := confirm(cmd.InOrStdin(), out, fmt.Sprintf("Retry %d migrations?", len(retryable)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("retry cancelled")
+		}
+	}
+
+	results := migrationService.RetryMigrations(ctx, retryable, services.RetryOptions{SourceToken: cfg.Migration.SourceToken})
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Fprintf(out, "FAILED %s: %v\n", result.Original.FullName(), result.Err)
+			continue
+		}
+		fmt.Fprintf(out, "STARTED %s: %s (%s)\n", result.Original.FullName(), result.Migration.ID, result.Migration.State)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d retries failed", failed, len(results))
+	}
+	return nil
+}
+
+// applySourceTokenFlag overrides the configured source token with the command line flag
+func applySourceTokenFlag(cfg *config.Config) {
+	if sourceToken != "" {
+		cfg.Migration.SourceToken = sourceToken
+	}
+}
+
+// firstLine returns the first line of a possibly multi-line text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+// confirm asks a yes/no question and reads the answer from r. Anything other than
+// y or yes, including end of input, counts as no.
+func confirm(r io.Reader, w io.Writer, question string) (bool, error) {
+	fmt.Fprintf(w, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
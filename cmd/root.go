@@ -2,24 +2,45 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/mona-actions/gh-migration-monitor/internal/api"
 	"github.com/mona-actions/gh-migration-monitor/internal/config"
+	"github.com/mona-actions/gh-migration-monitor/internal/history"
+	"github.com/mona-actions/gh-migration-monitor/internal/logs"
+	"github.com/mona-actions/gh-migration-monitor/internal/manifest"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
 	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/mona-actions/gh-migration-monitor/internal/state"
 	"github.com/mona-actions/gh-migration-monitor/internal/ui"
 	"github.com/rivo/tview"
 	"github.com/spf13/cobra"
 )
 
 var (
-	organization string
-	githubToken  string
-	legacy       bool
+	organizations     []string
+	enterprise        string
+	githubToken       string
+	hostname          string
+	legacy            bool
+	legacyConcurrency int
+	noHistory         bool
+	manifestPath      string
+	dashboardSince    string
+	dashboardUntil    string
+
+	// Notification flags
+	notifyBell     bool
+	notifyDesktop  string
+	notifyCommand  string
+	notifyWebhooks []string
 
 	// Version info
 	version   = "dev"
@@ -41,7 +62,12 @@ legacy migrations and the new GitHub Enterprise Importer (GEI) migrations.`,
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		os.Exit(exitCodeError)
 	}
 }
 
@@ -56,56 +82,268 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Required flags
-	rootCmd.Flags().StringVarP(&organization, "organization", "o", "", "GitHub organization to monitor (required)")
+	rootCmd.PersistentFlags().StringSliceVarP(&organizations, "organization", "o", nil, "GitHub organization to monitor (required, repeatable or comma-separated)")
 
 	// Optional flags
-	rootCmd.Flags().StringVarP(&githubToken, "github-token", "t", "", "GitHub token (can also be set via GHMM_GITHUB_TOKEN)")
-	rootCmd.Flags().BoolVarP(&legacy, "legacy", "l", false, "Monitor legacy migrations")
+	rootCmd.PersistentFlags().StringVar(&enterprise, "enterprise", "", "Monitor every organization in this enterprise, rediscovered on each refresh (can also be set via GHMM_GITHUB_ENTERPRISE)")
+	rootCmd.PersistentFlags().StringVarP(&githubToken, "github-token", "t", "", "GitHub token (can also be set via GHMM_GITHUB_TOKEN)")
+	rootCmd.PersistentFlags().StringVar(&hostname, "hostname", "", "GitHub hostname for GHES or GHE.com, e.g. github.example.com or example.ghe.com (can also be set via GHMM_GITHUB_HOSTNAME)")
+	rootCmd.PersistentFlags().BoolVarP(&legacy, "legacy", "l", false, "Monitor legacy migrations")
+	rootCmd.PersistentFlags().IntVar(&legacyConcurrency, "legacy-concurrency", 0, fmt.Sprintf("Number of legacy migration status queries run at once (default %d, can also be set via GHMM_MIGRATION_LEGACY_CONCURRENCY)", api.DefaultConcurrency))
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "Do not record migration state transitions to the history file")
+	rootCmd.PersistentFlags().StringVar(&manifestPath, "manifest", "", "CSV of repositories expected to be migrated, used to show repositories not started yet and flag unexpected migrations (can also be set via GHMM_MANIFEST_PATH)")
+
+	// Notification flags
+	rootCmd.Flags().BoolVar(&notifyBell, "bell", false, "Ring the terminal bell when a migration succeeds or fails")
+	rootCmd.Flags().StringVar(&notifyDesktop, "desktop-notify", "", "Send a desktop notification escape sequence when a migration succeeds or fails: osc9 or osc777")
+	rootCmd.Flags().StringVar(&notifyCommand, "notify-command", "", "Shell command to run when a migration succeeds or fails; the migration is passed as JSON on stdin")
+	rootCmd.Flags().StringArrayVar(&notifyWebhooks, "webhook", nil, "URL to POST migration state changes to as JSON (can be repeated)")
+
+	// Time window flags
+	rootCmd.Flags().StringVar(&dashboardSince, "since", "", "Only monitor migrations created at or after this date (2006-01-02), RFC 3339 timestamp or duration ago such as 48h")
+	rootCmd.Flags().StringVar(&dashboardUntil, "until", "", "Only monitor migrations created before this date, timestamp or duration ago")
+
+	// Retry flags
+	rootCmd.Flags().StringVar(&sourceToken, "source-token", "", "Token with access to the source repositories, used when retrying failed migrations (can also be set via GHMM_MIGRATION_SOURCE_TOKEN)")
 }
 
 func initConfig() {
 	// Configuration is handled by the config package
 }
 
-func runMigrationMonitor(cmd *cobra.Command, args []string) error {
-	// Check for required organization flag
-	if organization == "" {
-		return fmt.Errorf("organization is required. Use --organization flag or set GHMM_GITHUB_ORGANIZATION environment variable")
+// loadConfig loads the configuration, applies command line overrides and validates it
+func loadConfig() (*config.Config, error) {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return nil, err
 	}
 
+	// Check for required organization
+	if len(cfg.OrganizationNames()) == 0 && cfg.GitHub.Enterprise == "" {
+		return nil, fmt.Errorf("organization is required. Use --organization or --enterprise flag or set GHMM_GITHUB_ORGANIZATION environment variable")
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// loadClientConfig loads the configuration and applies command line overrides for
+// commands that talk to GitHub without needing an organization
+func loadClientConfig() (*config.Config, error) {
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Override config with command line flags
-	if organization != "" {
-		cfg.GitHub.Organization = organization
-	}
+	applyOrganizationFlag(cfg)
 	if githubToken != "" {
 		cfg.GitHub.Token = githubToken
 	}
+	if hostname != "" {
+		cfg.GitHub.Hostname = hostname
+	}
 	if legacy {
 		cfg.Migration.IsLegacy = legacy
 	}
+	if legacyConcurrency > 0 {
+		cfg.Migration.LegacyConcurrency = legacyConcurrency
+	}
+	if noHistory {
+		cfg.History.Enabled = false
+	}
+	if manifestPath != "" {
+		cfg.Manifest.Path = manifestPath
+	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+	if cfg.GitHub.Token == "" {
+		return nil, fmt.Errorf("invalid configuration: github token is required")
+	}
+
+	return cfg, nil
+}
+
+// applyOrganizationFlag replaces the configured organizations and enterprise with those given on the command line
+func applyOrganizationFlag(cfg *config.Config) {
+	if len(organizations) > 0 {
+		cfg.GitHub.Organization = ""
+		cfg.GitHub.Organizations = organizations
+	}
+	if enterprise != "" {
+		cfg.GitHub.Enterprise = enterprise
+	}
+}
+
+// newMigrationService creates the GitHub client and migration service for the configuration
+func newMigrationService(cfg *config.Config, opts ...api.ClientOption) (services.MigrationService, error) {
+	opts = append([]api.ClientOption{
+		api.WithHostname(cfg.GitHub.Hostname),
+		api.WithLegacyConcurrency(cfg.Migration.LegacyConcurrency),
+	}, opts...)
+	githubClient, err := api.NewGitHubClient(cfg.GitHub.Token, cfg.Migration.IsLegacy, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	return services.NewMigrationService(githubClient), nil
+}
+
+// newOrganizationResolver creates the resolver for the configured organizations and enterprise
+func newOrganizationResolver(cfg *config.Config, service services.MigrationService) *services.OrganizationResolver {
+	return services.NewOrganizationResolver(service, cfg.OrganizationNames(), cfg.GitHub.Enterprise)
+}
+
+// discoveryTimeout bounds enterprise organization discovery. Listing the
+// migrations of the organizations is bounded per organization by the service.
+const discoveryTimeout = 30 * time.Second
+
+// resolveOrganizations determines the organizations to monitor within discoveryTimeout
+func resolveOrganizations(ctx context.Context, resolver *services.OrganizationResolver) ([]string, error) {
+	discoveryCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	return resolver.Resolve(discoveryCtx)
+}
+
+// loadManifest loads the configured migration manifest, or returns nil if none is configured
+func loadManifest(cfg *config.Config) (*manifest.Manifest, error) {
+	if cfg.Manifest.Path == "" {
+		return nil, nil
+	}
+	return manifest.Load(cfg.Manifest.Path)
+}
+
+// newLogCache creates the migration log cache in the configured or default directory
+func newLogCache(cfg *config.Config, service services.MigrationService) (*logs.Cache, error) {
+	dir := cfg.Logs.Dir
+	if dir == "" {
+		var err error
+		if dir, err = logs.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return logs.NewCache(dir, service), nil
+}
+
+// openHistory opens the migration history store, or returns nil if history is disabled
+func openHistory(cfg *config.Config) (*history.Store, error) {
+	if !cfg.History.Enabled {
+		return nil, nil
+	}
+
+	path, err := historyPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration history: %w", err)
+	}
+	return store, nil
+}
+
+// historyPath returns the configured history file location or the default one
+func historyPath(cfg *config.Config) (string, error) {
+	if cfg.History.Path != "" {
+		return cfg.History.Path, nil
+	}
+	return history.DefaultPath()
+}
+
+func runMigrationMonitor(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
 
-	// Create GitHub client
-	githubClient, err := api.NewGitHubClient(cfg.GitHub.Token, cfg.Migration.IsLegacy)
+	// Override notification settings with command line flags
+	if notifyBell {
+		cfg.Notifications.Bell = notifyBell
+	}
+	if notifyDesktop != "" {
+		cfg.Notifications.Desktop = notifyDesktop
+	}
+	if notifyCommand != "" {
+		cfg.Notifications.Command = notifyCommand
+	}
+	for _, url := range notifyWebhooks {
+		cfg.Notifications.Webhooks = append(cfg.Notifications.Webhooks, config.WebhookConfig{URL: url})
+	}
+	applySourceTokenFlag(cfg)
+
+	window, err := services.ParseTimeWindow(dashboardSince, dashboardUntil)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
 	}
 
 	// Create migration service
-	migrationService := services.NewMigrationService(githubClient)
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Open the migration history store
+	historyStore, err := openHistory(cfg)
+	if err != nil {
+		return err
+	}
+	if historyStore != nil {
+		defer historyStore.Close()
+	}
+
+	// Determine the organizations to monitor on every refresh
+	resolver := newOrganizationResolver(cfg, migrationService)
+
+	// Detect changes between consecutive refreshes
+	detector := services.NewChangeDetector()
+
+	// Load the manifest of repositories expected to be migrated
+	waveManifest, err := loadManifest(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Repositories migrated outside the window would be reported as not started
+	if waveManifest != nil && !window.IsZero() {
+		return fmt.Errorf("--since and --until cannot be combined with a manifest")
+	}
 
 	// Create UI dashboard
 	dashboard := ui.NewDashboard()
+	dashboard.SetTimeWindow(window)
+	if historyStore != nil {
+		dashboard.SetFinishedAtFunc(func(migration models.Migration) (time.Time, bool) {
+			return historyStore.FinishedAt(migration.Organization, migration)
+		})
+	}
+	if waveManifest != nil {
+		dashboard.SetManifest(waveManifest)
+	}
+
+	// Notify about finished and failed migrations. Once the dashboard has stopped,
+	// delivery errors are printed instead.
+	var stopped atomic.Bool
+	dispatcher, err := newNotificationDispatcher(cfg, dashboard.TerminalWriter(), func(err error) {
+		if stopped.Load() {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		dashboard.ShowProgress(err.Error())
+	})
+	if err != nil {
+		return err
+	}
+	if !dispatcher.Empty() {
+		detector.Subscribe(dispatcher.Handle)
+	}
+
+	// Restore the table sort of the previous session and save it whenever it changes
+	restoreSort(dashboard)
 
 	// Setup TUI application
 	app := tview.NewApplication()
@@ -127,11 +365,44 @@ func runMigrationMonitor(cmd *cobra.Command, args []string) error {
 		}
 
 		dashboard.ShowRefreshing()
-		updateDashboard(ctx, migrationService, dashboard, cfg)
+		updateDashboard(ctx, migrationService, resolver, historyStore, detector, dashboard, cfg, window)
 		dashboard.HideRefreshing()
 	}
 	dashboard.SetRefreshFunc(refreshFunc)
 
+	// Retry failed migrations selected in the dashboard
+	dashboard.SetRetryFunc(func(migrations []models.Migration, dryRun bool) []services.RetryResult {
+		retryCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+
+		return migrationService.RetryMigrations(retryCtx, migrations, services.RetryOptions{
+			SourceToken: cfg.Migration.SourceToken,
+			DryRun:      dryRun,
+		})
+	})
+
+	// Show migration logs, which only GEI migrations have
+	if !cfg.Migration.IsLegacy {
+		logCache, err := newLogCache(cfg, migrationService)
+		if err != nil {
+			return err
+		}
+		dashboard.SetLogFunc(func(migration models.Migration, refresh bool) (string, error) {
+			logCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			defer cancel()
+
+			return logCache.Load(logCtx, migration, refresh)
+		})
+	}
+
+	// Abort queued and in-progress migrations selected in the dashboard
+	dashboard.SetAbortFunc(func(migrations []models.Migration) []services.AbortResult {
+		abortCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+
+		return migrationService.AbortMigrations(abortCtx, migrations, false)
+	})
+
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
@@ -163,19 +434,103 @@ func runMigrationMonitor(cmd *cobra.Command, args []string) error {
 	}()
 
 	// Run the application
-	return app.SetRoot(grid, true).SetFocus(grid).Run()
+	err = app.SetRoot(grid, true).SetFocus(grid).Run()
+
+	// Stop refreshing and give the notifications in flight a moment to be delivered
+	cancel()
+	stopped.Store(true)
+	if !dispatcher.Wait(5 * time.Second) {
+		fmt.Fprintln(os.Stderr, "Exiting before all notifications were delivered")
+	}
+	return err
 }
 
-func updateDashboard(ctx context.Context, service services.MigrationService, dashboard *ui.Dashboard, cfg *config.Config) {
-	// Create a timeout context for API calls to prevent hanging
-	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+// restoreSort sorts the dashboard's table like in the previous session and saves
+// the sort whenever it is changed. An unreadable state file starts unsorted, and
+// without a home directory the sort is not persisted at all.
+func restoreSort(dashboard *ui.Dashboard) {
+	path, err := state.DefaultPath()
+	if err != nil {
+		return
+	}
+
+	saved, err := state.Load(path)
+	if err == nil {
+		if column, err := ui.ParseSortColumn(saved.SortColumn); err == nil {
+			dashboard.SetSort(ui.TableSort{Column: column, Descending: saved.SortDescending})
+		}
+	}
+
+	dashboard.SetSortFunc(func(tableSort ui.TableSort) {
+		saved.SortColumn = string(tableSort.Column)
+		saved.SortDescending = tableSort.Descending
+		if err := state.Save(path, saved); err != nil {
+			dashboard.ShowProgress(err.Error())
+		}
+	})
+}
 
-	summary, err := service.ListMigrations(timeoutCtx, cfg.GitHub.Organization, cfg.Migration.IsLegacy)
+func updateDashboard(ctx context.Context, service services.MigrationService, resolver *services.OrganizationResolver, historyStore *history.Store, detector *services.ChangeDetector, dashboard *ui.Dashboard, cfg *config.Config, window services.TimeWindow) {
+	// Keep monitoring the last known organizations if enterprise discovery fails
+	discoveryFailures := 0
+	orgs, err := resolveOrganizations(ctx, resolver)
 	if err != nil {
-		// TODO: Add proper error handling/logging
+		discoveryFailures = 1
+	}
+	if len(orgs) == 0 {
+		dashboard.QueueUpdate(func() {
+			dashboard.RecordFailures(discoveryFailures)
+		})
 		return
 	}
 
-	dashboard.UpdateData(summary, cfg.GitHub.Organization)
+	var migrations []models.Migration
+	changes, failures := 0, 0
+	rerender := false
+
+	// Relative windows move with every refresh. The status filter is applied by the
+	// dashboard, as the change detector needs every migration of an organization.
+	opts := window.Apply(models.ListOptions{}, time.Now())
+	results := service.ListOrganizationsMigrations(ctx, orgs, opts, cfg.Migration.IsLegacy)
+	for _, result := range results {
+		if result.Err != nil {
+			// Keep showing the organization's last known migrations until it recovers
+			failures++
+			previous, _ := detector.Snapshot(result.Organization)
+			migrations = append(migrations, previous...)
+			continue
+		}
+
+		if historyStore != nil {
+			// A failed history write must not interrupt monitoring
+			_, _ = historyStore.Record(result.Organization, result.Summary.All(), time.Now())
+		}
+
+		// An organization seen for the first time has no events but must still be
+		// rendered, as must migrations that left a moving time window
+		if previous, seen := detector.Snapshot(result.Organization); !seen || len(previous) != len(result.Summary.All()) {
+			rerender = true
+		}
+		changes += len(detector.Observe(result.Organization, result.Summary.All()))
+		migrations = append(migrations, result.Summary.All()...)
+	}
+
+	// The dashboard's data is only changed on the event loop, which the key handlers use
+	summary := models.NewMigrationSummary(migrations)
+	dashboard.QueueUpdate(func() {
+		dashboard.RecordChanges(changes)
+		dashboard.RecordFailures(failures + discoveryFailures)
+		if failures == len(results) {
+			return
+		}
+
+		// Only re-render when something changed since the last refresh, including a
+		// new or removed organization
+		if changes == 0 && !rerender && dashboard.HasData() && slices.Equal(orgs, dashboard.Organizations()) {
+			dashboard.RefreshDurations()
+			return
+		}
+
+		dashboard.UpdateData(summary, orgs)
+	})
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/history"
+	"github.com/mona-actions/gh-migration-monitor/internal/metrics"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveMetricsAddr string
+	serveInterval    time.Duration
+)
+
+// serveCmd exports migration metrics for Prometheus instead of starting the dashboard
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Expose migration metrics on a Prometheus /metrics endpoint",
+	Long: `Poll the organizations' migrations periodically and expose them as Prometheus
+metrics on /metrics, so long migration waves can be graphed in Grafana and alerted on.
+
+Exported metrics include the number of migrations per organization and state bucket,
+counters for observed state transitions, refresh results, and the latency and error
+counts of the GitHub API requests made by the monitor.`,
+	Example: `  migration-monitor serve --organization myorg
+  migration-monitor serve --organization myorg --metrics-addr 127.0.0.1:9100 --interval 1m`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveMetricsAddr, "metrics-addr", "", "Address to serve metrics on (default \":9090\")")
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", 30*time.Second, "How often to poll for migration status")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if serveInterval <= 0 {
+		return fmt.Errorf("interval must be greater than zero")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if serveMetricsAddr != "" {
+		cfg.Metrics.Address = serveMetricsAddr
+	}
+
+	collector := metrics.NewCollector()
+
+	migrationService, err := newMigrationService(cfg, api.WithRequestObserver(collector.ObserveRequest))
+	if err != nil {
+		return err
+	}
+
+	historyStore, err := openHistory(cfg)
+	if err != nil {
+		return err
+	}
+	if historyStore != nil {
+		defer historyStore.Close()
+	}
+
+	// Count state transitions between refreshes
+	detector := services.NewChangeDetector()
+	detector.Subscribe(collector.ObserveEvent)
+
+	// Listen before polling so an unusable address fails immediately
+	listener, err := net.Listen("tcp", cfg.Metrics.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Metrics.Address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	monitored := strings.Join(cfg.OrganizationNames(), ", ")
+	if cfg.GitHub.Enterprise != "" {
+		monitored = fmt.Sprintf("enterprise %s", cfg.GitHub.Enterprise)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Serving metrics for %s on http://%s/metrics\n", monitored, listener.Addr())
+
+	resolver := newOrganizationResolver(cfg, migrationService)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(serveInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshMetrics(ctx, cmd.ErrOrStderr(), migrationService, resolver, historyStore, detector, collector, cfg.Migration.IsLegacy)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-serverErr:
+			return fmt.Errorf("metrics server failed: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshMetrics fetches the current migrations of every organization and updates the collector
+func refreshMetrics(ctx context.Context, errOut io.Writer, service services.MigrationService, resolver *services.OrganizationResolver, historyStore *history.Store, detector *services.ChangeDetector, collector *metrics.Collector, isLegacy bool) {
+	// Keep exporting the last known organizations if enterprise discovery fails
+	orgs, err := resolveOrganizations(ctx, resolver)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintf(errOut, "%s warning: %v\n", time.Now().Format("15:04:05"), err)
+	}
+
+	for _, result := range service.ListOrganizationsMigrations(ctx, orgs, models.ListOptions{}, isLegacy) {
+		collector.ObserveRefresh(result.Organization, result.Err, time.Now())
+		if result.Err != nil {
+			if ctx.Err() == nil {
+				// Transient API errors should not stop the exporter
+				fmt.Fprintf(errOut, "%s warning: %v\n", time.Now().Format("15:04:05"), result.Err)
+			}
+			continue
+		}
+
+		collector.ObserveSummary(result.Organization, result.Summary)
+
+		if historyStore != nil {
+			// A failed history write must not interrupt the exporter
+			_, _ = historyStore.Record(result.Organization, result.Summary.All(), time.Now())
+		}
+
+		detector.Observe(result.Organization, result.Summary.All())
+	}
+}
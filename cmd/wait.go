@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/history"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waitRepositories []string
+	waitMigrationIDs []string
+	waitInterval     time.Duration
+	waitTimeout      time.Duration
+)
+
+// waitCmd blocks until the selected migrations reach a terminal state
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for migrations to finish and exit with a CI-friendly code",
+	Long: `Poll the organizations until every migration, or the selected repositories and
+migration IDs, has succeeded or failed. Repositories may be given as org/repo when
+monitoring several organizations. Progress is printed periodically instead of
+starting the dashboard.
+
+Exit codes:
+  0  all selected migrations succeeded
+  1  the command could not run (configuration or API error)
+  2  one or more selected migrations failed
+  3  the timeout elapsed before all selected migrations finished`,
+	Example: `  migration-monitor wait --organization myorg
+  migration-monitor wait --organization myorg --repository frontend --repository backend --timeout 2h
+  migration-monitor wait --organization myorg --migration-id RM_kgDaACQ... --interval 1m`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runWait,
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+
+	waitCmd.Flags().StringSliceVarP(&waitRepositories, "repository", "r", nil, "Repository name, or org/repo, to wait for (repeatable)")
+	waitCmd.Flags().StringSliceVar(&waitMigrationIDs, "migration-id", nil, "Migration ID to wait for (repeatable)")
+	waitCmd.Flags().DurationVar(&waitInterval, "interval", 30*time.Second, "How often to poll for migration status")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 0, "Give up after this long (0 waits indefinitely)")
+}
+
+func runWait(cmd *cobra.Command, args []string) error {
+	if waitInterval <= 0 {
+		return fmt.Errorf("interval must be greater than zero")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	migrationService, err := newMigrationService(cfg)
+	if err != nil {
+		return err
+	}
+
+	historyStore, err := openHistory(cfg)
+	if err != nil {
+		return err
+	}
+	if historyStore != nil {
+		defer historyStore.Close()
+	}
+
+	ctx := cmd.Context()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	resolver := newOrganizationResolver(cfg, migrationService)
+
+	target := services.WaitTarget{
+		Repositories: waitRepositories,
+		MigrationIDs: waitMigrationIDs,
+	}
+	out := cmd.OutOrStdout()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := pollWaitStatus(ctx, migrationService, resolver, historyStore, cfg.Migration.IsLegacy, target)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return withExitCode(exitCodeTimeout, "timed out after %s waiting for migrations", waitTimeout)
+			}
+			// Transient API errors should not fail a long-running wait
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s warning: %v\n", time.Now().Format("15:04:05"), err)
+		} else {
+			printWaitProgress(out, status)
+
+			if status.Done() {
+				return finishWait(out, status)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return withExitCode(exitCodeTimeout, "timed out after %s waiting for migrations", waitTimeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollWaitStatus fetches the current migrations and evaluates the wait target against them
+func pollWaitStatus(ctx context.Context, service services.MigrationService, resolver *services.OrganizationResolver, historyStore *history.Store, isLegacy bool, target services.WaitTarget) (services.WaitStatus, error) {
+	orgs, err := resolveOrganizations(ctx, resolver)
+	if err != nil {
+		return services.WaitStatus{}, err
+	}
+
+	results := service.ListOrganizationsMigrations(ctx, orgs, models.ListOptions{}, isLegacy)
+
+	if historyStore != nil {
+		// A failed history write must not interrupt waiting
+		for _, result := range results {
+			if result.Err == nil {
+				_, _ = historyStore.Record(result.Organization, result.Summary.All(), time.Now())
+			}
+		}
+	}
+
+	// Evaluating a partial result could report missing migrations, so retry instead
+	summary, err := services.MergeResults(results)
+	if err != nil {
+		return services.WaitStatus{}, err
+	}
+
+	return services.EvaluateWait(summary, target), nil
+}
+
+// printWaitProgress prints a single progress line
+func printWaitProgress(w io.Writer, status services.WaitStatus) {
+	fmt.Fprintf(w, "%s %d/%d complete (succeeded: %d, failed: %d, in progress: %d, queued: %d, not found: %d)\n",
+		time.Now().Format("15:04:05"),
+		status.Completed(), status.Total,
+		status.Succeeded, status.Failed, status.InProgress, status.Queued, status.Missing,
+	)
+}
+
+// finishWait reports the final outcome and returns an exit error if any migration failed
+func finishWait(w io.Writer, status services.WaitStatus) error {
+	if status.Failed == 0 {
+		fmt.Fprintf(w, "All %d migrations succeeded\n", status.Succeeded)
+		return nil
+	}
+
+	for _, migration := range status.FailedMigrations {
+		fmt.Fprintf(w, "FAILED %s (%s): %s\n", migration.RepositoryName, migration.ID, migration.FailureReason)
+	}
+
+	return withExitCode(exitCodeMigrationsFailed, "%d of %d migrations failed", status.Failed, status.Total)
+}
@@ -9,7 +9,12 @@
 //   - cmd/: Command-line interface and CLI parsing
 //   - internal/api/: GitHub API client implementations
 //   - internal/config/: Configuration management
+//   - internal/history/: Persistent migration state history
+//   - internal/logs/: Migration log download cache and export
+//   - internal/manifest/: Migration manifest reconciliation
+//   - internal/metrics/: Prometheus metrics export
 //   - internal/models/: Domain models and business entities
+//   - internal/notify/: Notifications for finished and failed migrations
 //   - internal/services/: Business logic services
 //   - internal/ui/: Terminal user interface components
 //
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// snapshotMaxAge is how long cached finished migrations are trusted before an
+// organization's migrations are listed in full again, e.g. to drop deleted ones
+const snapshotMaxAge = 30 * time.Minute
+
+// geiSnapshot holds an organization's oldest GEI migrations once every one of
+// them has finished. Finished migrations never change, so only migrations created
+// after the watermark have to be listed again.
+type geiSnapshot struct {
+	// finished holds every migration created at or before the watermark
+	finished []models.Migration
+	// watermark is the creation time of the newest migration in finished
+	watermark time.Time
+	// listedAt is when the organization's migrations were last listed in full
+	listedAt time.Time
+}
+
+// snapshotCache keeps the GEI snapshots of every listing made by a client, keyed by
+// organization and repository
+type snapshotCache struct {
+	mu        sync.Mutex
+	snapshots map[string]*geiSnapshot
+}
+
+// newSnapshotCache creates an empty snapshot cache
+func newSnapshotCache() *snapshotCache {
+	return &snapshotCache{snapshots: make(map[string]*geiSnapshot)}
+}
+
+// snapshotKey identifies the migrations selected by list options. Listings by
+// state have no snapshot, so only the organization and repository are part of it.
+func snapshotKey(opts models.ListOptions) string {
+	return opts.Organization + "\x00" + opts.RepositoryName
+}
+
+// get returns the listing's snapshot, or nil if it has none or it expired
+func (c *snapshotCache) get(key string, now time.Time) *geiSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := c.snapshots[key]
+	if snapshot == nil || now.Sub(snapshot.listedAt) > snapshotMaxAge {
+		return nil
+	}
+	return snapshot
+}
+
+// update replaces the listing's snapshot with the finished migrations at the old
+// end of migrations, which must be every migration the listing selects.
+// listedAt is when the migrations were last listed in full.
+func (c *snapshotCache) update(key string, migrations []models.Migration, listedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Oldest first, so the finished migrations form a prefix
+	sorted := append([]models.Migration(nil), migrations...)
+	sortByCreatedAt(sorted)
+
+	n := 0
+	for n < len(sorted) && isFinished(sorted[n]) {
+		n++
+	}
+
+	// Listing stops at the watermark, so a migration created at the same time as the
+	// first unfinished one could be missed unless it is left out of the snapshot too
+	for n > 0 && n < len(sorted) && sorted[n-1].CreatedAt.Equal(sorted[n].CreatedAt) {
+		n--
+	}
+
+	if n == 0 {
+		delete(c.snapshots, key)
+		return
+	}
+
+	c.snapshots[key] = &geiSnapshot{
+		finished:  sorted[:n:n],
+		watermark: sorted[n-1].CreatedAt,
+		listedAt:  listedAt,
+	}
+}
+
+// merge returns the listed migrations together with the finished migrations of
+// the snapshot that were not listed again, oldest first
+func (s *geiSnapshot) merge(listed []models.Migration) []models.Migration {
+	seen := make(map[string]bool, len(listed))
+	for _, migration := range listed {
+		seen[migration.ID] = true
+	}
+
+	merged := append([]models.Migration(nil), listed...)
+	for _, migration := range s.finished {
+		if !seen[migration.ID] {
+			merged = append(merged, migration)
+		}
+	}
+	sortByCreatedAt(merged)
+	return merged
+}
+
+// legacyCache keeps the migrations of finished legacy migrations by GUID, so their
+// migratable resources are only queried once
+type legacyCache struct {
+	mu   sync.Mutex
+	rows map[string][]models.Migration
+}
+
+// newLegacyCache creates an empty legacy migration cache
+func newLegacyCache() *legacyCache {
+	return &legacyCache{rows: make(map[string][]models.Migration)}
+}
+
+// get returns the cached migrations of a legacy migration
+func (c *legacyCache) get(guid string) ([]models.Migration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rows, ok := c.rows[guid]
+	return rows, ok
+}
+
+// put caches the migrations of a finished legacy migration
+func (c *legacyCache) put(guid string, rows []models.Migration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rows[guid] = rows
+}
+
+// isFinished returns true for migrations whose state can no longer change
+func isFinished(migration models.Migration) bool {
+	return migration.State.IsSucceeded() || migration.State.IsFailed()
+}
+
+// sortByCreatedAt orders migrations oldest first, keeping the order of migrations
+// created at the same time
+func sortByCreatedAt(migrations []models.Migration) {
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].CreatedAt.Before(migrations[j].CreatedAt)
+	})
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// geiPageSize is the number of migrations per page served by geiGraphQL
+const geiPageSize = 2
+
+// geiMigration is a GEI migration served by geiGraphQL
+type geiMigration struct {
+	id        string
+	state     models.State
+	createdAt time.Time
+}
+
+// geiGraphQL answers repositoryMigrations queries with the migrations, newest
+// first, geiPageSize at a time and filtered by the state variable. The cursor is
+// the index of the next migration. migrations is read on every query, so tests
+// can change it between listings.
+func geiGraphQL(migrations *[]geiMigration) func(req graphQLRequest) string {
+	return func(req graphQLRequest) string {
+		state, _ := req.Variables["state"].(string)
+
+		var selected []geiMigration
+		for _, migration := range *migrations {
+			if state == "" || string(migration.state) == state {
+				selected = append(selected, migration)
+			}
+		}
+
+		start := 0
+		if after, ok := req.Variables["after"].(string); ok {
+			start, _ = strconv.Atoi(after)
+		}
+		end := min(start+geiPageSize, len(selected))
+
+		var edges []string
+		for _, migration := range selected[start:end] {
+			edges = append(edges, fmt.Sprintf(`{"node":{"id":%q,"repositoryName":%q,"state":%q,"createdAt":%q}}`,
+				migration.id, "repo-"+migration.id, migration.state, migration.createdAt.Format(time.RFC3339)))
+		}
+		return fmt.Sprintf(`{"data":{"organization":{"repositoryMigrations":{"pageInfo":{"hasNextPage":%t,"endCursor":"%d"},"edges":[%s]}}}}`,
+			end < len(selected), end, strings.Join(edges, ","))
+	}
+}
+
+// listGEI lists the organization's GEI migrations and returns their IDs and states
+// oldest first, along with the number of queries the listing made
+func listGEI(t *testing.T, client *githubClient, server *fakeGitHub, opts models.ListOptions) (string, int) {
+	t.Helper()
+
+	before := len(server.queries)
+	opts.Organization = "org"
+	migrations, err := client.ListMigrations(context.Background(), opts, false)
+	if err != nil {
+		t.Fatalf("ListMigrations: %v", err)
+	}
+
+	var listed []string
+	for _, migration := range migrations {
+		listed = append(listed, migration.ID+"="+string(migration.State))
+	}
+	return strings.Join(listed, " "), len(server.queries) - before
+}
+
+func TestListGEIMigrationsStopsAtWatermark(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return created.Add(time.Duration(n) * time.Hour) }
+
+	migrations := []geiMigration{
+		{"m5", models.StateInProgress, hour(5)},
+		{"m4", models.StateSucceeded, hour(4)},
+		{"m3", models.StateFailed, hour(3)},
+		{"m2", models.StateSucceeded, hour(2)},
+		{"m1", models.StateSucceeded, hour(1)},
+	}
+	server := newFakeGitHub(t, geiGraphQL(&migrations), nil)
+	client := newTestClient(t, server, false)
+
+	const want = "m1=SUCCEEDED m2=SUCCEEDED m3=FAILED m4=SUCCEEDED m5=IN_PROGRESS"
+	listed, queries := listGEI(t, client, server, models.ListOptions{})
+	if listed != want || queries != 3 {
+		t.Fatalf("first listing = %q in %d queries, want %q in 3", listed, queries, want)
+	}
+
+	// m4 is the watermark, so the first page is enough
+	listed, queries = listGEI(t, client, server, models.ListOptions{})
+	if listed != want || queries != 1 {
+		t.Errorf("second listing = %q in %d queries, want %q in 1", listed, queries, want)
+	}
+
+	// A new migration pushes the watermark to the second page
+	migrations = append([]geiMigration{{"m6", models.StateQueued, hour(6)}}, migrations...)
+	migrations[1].state = models.StateSucceeded
+	listed, queries = listGEI(t, client, server, models.ListOptions{})
+	if want := "m1=SUCCEEDED m2=SUCCEEDED m3=FAILED m4=SUCCEEDED m5=SUCCEEDED m6=QUEUED"; listed != want || queries != 2 {
+		t.Errorf("third listing = %q in %d queries, want %q in 2", listed, queries, want)
+	}
+}
+
+func TestListGEIMigrationsFinishingBelowUnfinished(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return created.Add(time.Duration(n) * time.Hour) }
+
+	migrations := []geiMigration{
+		{"m5", models.StateSucceeded, hour(5)},
+		{"m4", models.StateSucceeded, hour(4)},
+		{"m3", models.StateInProgress, hour(3)},
+		{"m2", models.StateSucceeded, hour(2)},
+		{"m1", models.StateSucceeded, hour(1)},
+	}
+	server := newFakeGitHub(t, geiGraphQL(&migrations), nil)
+	client := newTestClient(t, server, false)
+
+	listGEI(t, client, server, models.ListOptions{})
+
+	// m3 is above the watermark m2, so its failure is listed on the second page
+	migrations[2].state = models.StateFailed
+	listed, queries := listGEI(t, client, server, models.ListOptions{})
+	if want := "m1=SUCCEEDED m2=SUCCEEDED m3=FAILED m4=SUCCEEDED m5=SUCCEEDED"; listed != want || queries != 2 {
+		t.Errorf("second listing = %q in %d queries, want %q in 2", listed, queries, want)
+	}
+
+	// Every migration has finished, so the watermark moves to m5
+	listed, queries = listGEI(t, client, server, models.ListOptions{})
+	if want := "m1=SUCCEEDED m2=SUCCEEDED m3=FAILED m4=SUCCEEDED m5=SUCCEEDED"; listed != want || queries != 1 {
+		t.Errorf("third listing = %q in %d queries, want %q in 1", listed, queries, want)
+	}
+}
+
+func TestListGEIMigrationsByStateIsNotCached(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return created.Add(time.Duration(n) * time.Hour) }
+
+	migrations := []geiMigration{
+		{"m4", models.StateFailed, hour(4)},
+		{"m3", models.StateFailed, hour(3)},
+		{"m2", models.StateInProgress, hour(2)},
+		{"m1", models.StateFailed, hour(1)},
+	}
+	server := newFakeGitHub(t, geiGraphQL(&migrations), nil)
+	client := newTestClient(t, server, false)
+
+	opts := models.ListOptions{State: models.StateFailed}
+	if listed, _ := listGEI(t, client, server, opts); listed != "m1=FAILED m3=FAILED m4=FAILED" {
+		t.Fatalf("first listing = %q, want m1, m3 and m4", listed)
+	}
+
+	// m2 fails on the second page, below the newest failed migration, and must
+	// still be listed
+	migrations[2].state = models.StateFailed
+	if listed, _ := listGEI(t, client, server, opts); listed != "m1=FAILED m2=FAILED m3=FAILED m4=FAILED" {
+		t.Errorf("second listing = %q, want m1, m2, m3 and m4", listed)
+	}
+}
@@ -2,14 +2,30 @@ package api
 
 import (
 	"context"
+	"io"
 
 	"github.com/mona-actions/gh-migration-monitor/internal/models"
 )
 
 // GitHubClient defines the interface for GitHub API operations
 type GitHubClient interface {
-	// ListMigrations returns all migrations for the specified organization
-	ListMigrations(ctx context.Context, org string, isLegacy bool) ([]models.Migration, error)
+	// ListMigrations returns the migrations of opts.Organization selected by opts
+	ListMigrations(ctx context.Context, opts models.ListOptions, isLegacy bool) ([]models.Migration, error)
+
+	// GetMigration returns a single GEI migration by ID
+	GetMigration(ctx context.Context, id string) (models.Migration, error)
+
+	// DownloadMigrationLog writes the contents of a migration log to w
+	DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error
+
+	// ListEnterpriseOrganizations returns the logins of every organization in the enterprise
+	ListEnterpriseOrganizations(ctx context.Context, enterprise string) ([]string, error)
+
+	// StartRepositoryMigration starts a GEI repository migration and returns it
+	StartRepositoryMigration(ctx context.Context, input StartMigrationInput) (models.Migration, error)
+
+	// AbortRepositoryMigration aborts a queued or in-progress GEI migration
+	AbortRepositoryMigration(ctx context.Context, migrationID string) error
 }
 
 // APIError represents an API error
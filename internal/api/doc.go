@@ -5,5 +5,7 @@
 // migrations and GitHub Enterprise Importer (GEI) migrations.
 //
 // The package implements rate limiting, error handling, and authentication
-// following GitHub API best practices.
+// following GitHub API best practices. Migrations that have finished are cached
+// by the client, so repeated listings only fetch the migrations that can still
+// change, and REST listings are made conditional with ETags.
 package api
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultHostname is the hostname used when none is configured
+	DefaultHostname = "github.com"
+
+	dotcomAPIHost  = "api.github.com"
+	dataResidency  = ".ghe.com"
+	defaultScheme  = "https"
+	restPathGHES   = "/api/v3/"
+	graphQLPathAPI = "/graphql"
+	graphQLGHES    = "/api/graphql"
+)
+
+// Endpoints holds the REST and GraphQL API URLs for a GitHub instance
+type Endpoints struct {
+	REST    string
+	GraphQL string
+}
+
+// ResolveEndpoints derives the REST and GraphQL API URLs for the given hostname.
+//
+// An empty hostname or github.com resolves to api.github.com. Hostnames ending in
+// .ghe.com are treated as GHE.com data residency tenants served from api.<tenant>.ghe.com,
+// and any other hostname is treated as a GitHub Enterprise Server instance. A scheme
+// may be included (e.g. http://127.0.0.1:8080) to target a plain HTTP server.
+func ResolveEndpoints(hostname string) (Endpoints, error) {
+	scheme, host, err := parseHostname(hostname)
+	if err != nil {
+		return Endpoints{}, err
+	}
+
+	switch {
+	case host == DefaultHostname || host == dotcomAPIHost:
+		base := fmt.Sprintf("%s://%s", scheme, dotcomAPIHost)
+		return Endpoints{REST: base + "/", GraphQL: base + graphQLPathAPI}, nil
+	case strings.HasSuffix(host, dataResidency):
+		if !strings.HasPrefix(host, "api.") {
+			host = "api." + host
+		}
+		base := fmt.Sprintf("%s://%s", scheme, host)
+		return Endpoints{REST: base + "/", GraphQL: base + graphQLPathAPI}, nil
+	default:
+		base := fmt.Sprintf("%s://%s", scheme, host)
+		return Endpoints{REST: base + restPathGHES, GraphQL: base + graphQLGHES}, nil
+	}
+}
+
+// parseHostname splits a user-supplied hostname into scheme and host
+func parseHostname(hostname string) (string, string, error) {
+	hostname = strings.TrimSpace(hostname)
+	if hostname == "" {
+		return defaultScheme, DefaultHostname, nil
+	}
+
+	if !strings.Contains(hostname, "://") {
+		hostname = defaultScheme + "://" + hostname
+	}
+
+	u, err := url.Parse(hostname)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid hostname %q: %w", hostname, err)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("invalid hostname %q", hostname)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("invalid hostname %q: unsupported scheme %s", hostname, u.Scheme)
+	}
+
+	return u.Scheme, strings.ToLower(u.Host), nil
+}
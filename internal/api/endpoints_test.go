@@ -0,0 +1,71 @@
+package api
+
+import "testing"
+
+func TestResolveEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		want     Endpoints
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			hostname: "",
+			want:     Endpoints{REST: "https://api.github.com/", GraphQL: "https://api.github.com/graphql"},
+		},
+		{
+			name:     "github.com",
+			hostname: "github.com",
+			want:     Endpoints{REST: "https://api.github.com/", GraphQL: "https://api.github.com/graphql"},
+		},
+		{
+			name:     "github.com API host with scheme",
+			hostname: "https://API.github.com",
+			want:     Endpoints{REST: "https://api.github.com/", GraphQL: "https://api.github.com/graphql"},
+		},
+		{
+			name:     "GHE.com tenant",
+			hostname: "octocorp.ghe.com",
+			want:     Endpoints{REST: "https://api.octocorp.ghe.com/", GraphQL: "https://api.octocorp.ghe.com/graphql"},
+		},
+		{
+			name:     "GHE.com API host",
+			hostname: "api.octocorp.ghe.com",
+			want:     Endpoints{REST: "https://api.octocorp.ghe.com/", GraphQL: "https://api.octocorp.ghe.com/graphql"},
+		},
+		{
+			name:     "GHES",
+			hostname: "ghes.example.com",
+			want:     Endpoints{REST: "https://ghes.example.com/api/v3/", GraphQL: "https://ghes.example.com/api/graphql"},
+		},
+		{
+			name:     "GHES over HTTP with port",
+			hostname: "http://127.0.0.1:8080",
+			want:     Endpoints{REST: "http://127.0.0.1:8080/api/v3/", GraphQL: "http://127.0.0.1:8080/api/graphql"},
+		},
+		{
+			name:     "unsupported scheme",
+			hostname: "ftp://ghes.example.com",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveEndpoints(tt.hostname)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveEndpoints(%q) = %+v, want an error", tt.hostname, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEndpoints(%q): %v", tt.hostname, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveEndpoints(%q) = %+v, want %+v", tt.hostname, got, tt.want)
+			}
+		})
+	}
+}
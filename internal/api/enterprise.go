@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// ListEnterpriseOrganizations implements GitHubClient.ListEnterpriseOrganizations
+func (c *githubClient) ListEnterpriseOrganizations(ctx context.Context, enterprise string) ([]string, error) {
+	var query struct {
+		Enterprise struct {
+			Organizations struct {
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage githubv4.Boolean
+				}
+				Nodes []struct {
+					Login string
+				}
+			} `graphql:"organizations(first: $first, after: $after)"`
+		} `graphql:"enterprise(slug: $slug)"`
+	}
+
+	variables := map[string]interface{}{
+		"slug":  githubv4.String(enterprise),
+		"first": githubv4.Int(100),
+		"after": (*githubv4.String)(nil),
+	}
+
+	var organizations []string
+
+	for {
+		if err := c.graphqlClient.Query(ctx, &query, variables); err != nil {
+			return nil, &APIError{
+				StatusCode: 0,
+				Message:    fmt.Sprintf("failed to list organizations for enterprise %s", enterprise),
+				Err:        err,
+			}
+		}
+
+		for _, node := range query.Enterprise.Organizations.Nodes {
+			organizations = append(organizations, node.Login)
+		}
+
+		if !query.Enterprise.Organizations.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = githubv4.NewString(query.Enterprise.Organizations.PageInfo.EndCursor)
+	}
+
+	return organizations, nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// enterprisePage is one page of an enterprise's organizations
+type enterprisePage struct {
+	logins      []string
+	hasNextPage bool
+	endCursor   string
+}
+
+// enterpriseGraphQL answers enterprise organization queries from pages keyed by the
+// after cursor, where the first page has the cursor ""
+func enterpriseGraphQL(pages map[string]enterprisePage) func(req graphQLRequest) string {
+	return func(req graphQLRequest) string {
+		after, _ := req.Variables["after"].(string)
+		page, ok := pages[after]
+		if !ok || req.Variables["slug"] != "octocorp" {
+			return `{"errors":[{"message":"unexpected query"}]}`
+		}
+
+		var nodes []string
+		for _, login := range page.logins {
+			nodes = append(nodes, fmt.Sprintf(`{"login":%q}`, login))
+		}
+		return fmt.Sprintf(`{"data":{"enterprise":{"organizations":{"pageInfo":{"hasNextPage":%t,"endCursor":%q},"nodes":[%s]}}}}`,
+			page.hasNextPage, page.endCursor, strings.Join(nodes, ","))
+	}
+}
+
+func TestListEnterpriseOrganizationsPages(t *testing.T) {
+	server := newFakeGitHub(t, enterpriseGraphQL(map[string]enterprisePage{
+		"":   {logins: []string{"acme", "acme-emu"}, hasNextPage: true, endCursor: "p2"},
+		"p2": {logins: []string{"acme-labs", "acme-ops"}, hasNextPage: true, endCursor: "p3"},
+		"p3": {logins: []string{"acme-sandbox"}},
+	}), nil)
+	client := newTestClient(t, server, false)
+
+	organizations, err := client.ListEnterpriseOrganizations(context.Background(), "octocorp")
+	if err != nil {
+		t.Fatalf("ListEnterpriseOrganizations: %v", err)
+	}
+
+	want := "acme acme-emu acme-labs acme-ops acme-sandbox"
+	if got := strings.Join(organizations, " "); got != want {
+		t.Errorf("organizations = %q, want %q", got, want)
+	}
+
+	var cursors []any
+	for _, query := range server.queries {
+		cursors = append(cursors, query.Variables["after"])
+	}
+	if fmt.Sprint(cursors) != fmt.Sprint([]any{nil, "p2", "p3"}) {
+		t.Errorf("after cursors = %v, want [<nil> p2 p3]", cursors)
+	}
+}
+
+func TestListEnterpriseOrganizationsError(t *testing.T) {
+	server := newFakeGitHub(t, enterpriseGraphQL(nil), nil)
+	client := newTestClient(t, server, false)
+
+	organizations, err := client.ListEnterpriseOrganizations(context.Background(), "octocorp")
+	if err == nil {
+		t.Fatalf("ListEnterpriseOrganizations returned %v, want an error", organizations)
+	}
+	if !strings.Contains(err.Error(), "enterprise octocorp") {
+		t.Errorf("error = %q, want it to name the enterprise", err)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// etagResponse is a cached REST response and the ETag it was returned with
+type etagResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// etagTransport makes GET requests conditional on the ETag of the previous response
+// to the same URL and replays that response when GitHub answers 304 Not Modified.
+// Conditional requests that are not modified do not count against the REST rate limit.
+type etagTransport struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	responses map[string]etagResponse
+}
+
+// newETagTransport wraps a transport with conditional GET requests
+func newETagTransport(next http.RoundTripper) *etagTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &etagTransport{
+		next:      next,
+		responses: make(map[string]etagResponse),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	t.mu.Lock()
+	cached, ok := t.responses[key]
+	t.mu.Unlock()
+
+	if ok {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        mergeHeaders(cached.header, resp.Header),
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       resp.Request,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	t.responses[key] = etagResponse{etag: etag, header: resp.Header.Clone(), body: body}
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// mergeHeaders returns the cached headers updated with those of the 304 response,
+// which carry the current rate limit, keeping the cached body's length
+func mergeHeaders(cached, current http.Header) http.Header {
+	merged := cached.Clone()
+	for name, values := range current {
+		if name == "Content-Length" {
+			continue
+		}
+		merged[name] = values
+	}
+	return merged
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestETagTransportReplaysNotModified(t *testing.T) {
+	var mu sync.Mutex
+	etag, body := `"v1"`, `{"version":1}`
+	var conditional []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(100-len(conditional)))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newETagTransport(nil)}
+	get := func() (*http.Response, string) {
+		t.Helper()
+		resp, err := client.Get(server.URL + "/orgs/org/migrations")
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading body: %v", err)
+		}
+		return resp, string(data)
+	}
+
+	get()
+
+	// The second request is conditional and replays the cached body
+	resp, data := get()
+	if resp.StatusCode != http.StatusOK || data != `{"version":1}` {
+		t.Errorf("replayed response = %d %q, want 200 with the cached body", resp.StatusCode, data)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want the cached application/json", got)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "98" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 98 from the 304 response", got)
+	}
+
+	// A changed resource is returned and cached with its new ETag
+	mu.Lock()
+	etag, body = `"v2"`, `{"version":2}`
+	mu.Unlock()
+	if _, data := get(); data != `{"version":2}` {
+		t.Errorf("changed response body = %q, want the new body", data)
+	}
+	if _, data := get(); data != `{"version":2}` {
+		t.Errorf("replayed response body = %q, want the new body", data)
+	}
+
+	want := []string{"", `"v1"`, `"v1"`, `"v2"`}
+	if fmt.Sprint(conditional) != fmt.Sprint(want) {
+		t.Errorf("If-None-Match headers = %q, want %q", conditional, want)
+	}
+}
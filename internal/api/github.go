@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -17,17 +19,70 @@ import (
 
 // githubClient implements the GitHubClient interface
 type githubClient struct {
+	token         string
+	hosts         []string
 	restClient    *github.Client
 	graphqlClient *githubv4.Client
 	rateLimiter   *http.Client
+
+	// legacyConcurrency limits the concurrent legacy migration status queries
+	legacyConcurrency int
+
+	// Finished migrations, which are not listed or queried again
+	snapshots *snapshotCache
+	legacy    *legacyCache
+}
+
+// ClientOption configures optional behaviour of the GitHub API client
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the settings applied by ClientOption values
+type clientOptions struct {
+	hostname          string
+	observers         []RequestObserver
+	legacyConcurrency int
+}
+
+// WithHostname targets a GitHub Enterprise Server or GHE.com host instead of github.com
+func WithHostname(hostname string) ClientOption {
+	return func(o *clientOptions) {
+		o.hostname = hostname
+	}
+}
+
+// WithLegacyConcurrency sets how many legacy migration status queries run at once.
+// A value below one uses DefaultConcurrency.
+func WithLegacyConcurrency(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.legacyConcurrency = n
+	}
 }
 
 // NewGitHubClient creates a new GitHub API client
-func NewGitHubClient(token string, isLegacy bool) (GitHubClient, error) {
+func NewGitHubClient(token string, isLegacy bool, opts ...ClientOption) (GitHubClient, error) {
 	if token == "" {
 		return nil, fmt.Errorf("github token is required")
 	}
 
+	options := clientOptions{hostname: DefaultHostname}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	endpoints, err := ResolveEndpoints(options.hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	restURL, err := url.Parse(endpoints.REST)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REST endpoint %s: %w", endpoints.REST, err)
+	}
+
+	// Hosts that may receive the token outside of API calls, e.g. to download logs
+	_, host, _ := parseHostname(options.hostname)
+	hosts := []string{host, strings.ToLower(restURL.Host)}
+
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	tc := oauth2.NewClient(ctx, ts)
@@ -43,15 +98,32 @@ func NewGitHubClient(token string, isLegacy bool) (GitHubClient, error) {
 		})
 	}
 
+	// Report every request, including those retried by the rate limiter
+	if len(options.observers) > 0 {
+		tc.Transport = observeTransport(tc.Transport, endpoints.GraphQL, options.observers)
+	}
+
 	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(tc.Transport)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create rate limiter: %w", err)
 	}
 
+	// Point the REST client at the resolved host; uploads are not used by this tool.
+	// REST listings are conditional, so unchanged pages cost no rate limit.
+	restClient := github.NewClient(&http.Client{Transport: newETagTransport(rateLimiter.Transport)})
+	restClient.BaseURL = restURL
+	restClient.UploadURL = restURL
+
 	return &githubClient{
-		restClient:    github.NewClient(rateLimiter),
-		graphqlClient: githubv4.NewClient(rateLimiter),
+		token:         token,
+		hosts:         hosts,
+		restClient:    restClient,
+		graphqlClient: githubv4.NewEnterpriseClient(endpoints.GraphQL, rateLimiter),
 		rateLimiter:   rateLimiter,
+
+		legacyConcurrency: options.legacyConcurrency,
+		snapshots:         newSnapshotCache(),
+		legacy:            newLegacyCache(),
 	}, nil
 }
 
@@ -62,16 +134,62 @@ func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
-// ListMigrations implements GitHubClient.ListMigrations
-func (c *githubClient) ListMigrations(ctx context.Context, org string, isLegacy bool) ([]models.Migration, error) {
+// ListMigrations implements GitHubClient.ListMigrations. GEI migrations are filtered
+// by state and repository name on the server, legacy migrations after listing them.
+func (c *githubClient) ListMigrations(ctx context.Context, opts models.ListOptions, isLegacy bool) ([]models.Migration, error) {
 	if isLegacy {
-		return c.listLegacyMigrations(ctx, org)
+		migrations, err := c.listLegacyMigrations(ctx, opts.Organization, opts.Page)
+		if err != nil {
+			return nil, err
+		}
+		return limitMigrations(filterLegacyMigrations(migrations, opts), opts.Limit), nil
 	}
-	return c.listGEIMigrations(ctx, org)
+	return c.listGEIMigrations(ctx, opts)
 }
 
-// listGEIMigrations retrieves migrations using the new GEI API
-func (c *githubClient) listGEIMigrations(ctx context.Context, org string) ([]models.Migration, error) {
+// filterLegacyMigrations returns the legacy migrations matching the state, time
+// window and repository name of the options. Legacy repository names are URLs, so the
+// repository name matches their last path segment.
+func filterLegacyMigrations(migrations []models.Migration, opts models.ListOptions) []models.Migration {
+	if opts.State == "" && opts.RepositoryName == "" && opts.Since.IsZero() && opts.Until.IsZero() {
+		return migrations
+	}
+
+	var filtered []models.Migration
+	for _, migration := range migrations {
+		if opts.State != "" && migration.State != opts.State {
+			continue
+		}
+		if !opts.Since.IsZero() && migration.CreatedAt.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && !migration.CreatedAt.Before(opts.Until) {
+			continue
+		}
+		if opts.RepositoryName != "" && !strings.EqualFold(path.Base(migration.RepositoryName), opts.RepositoryName) {
+			continue
+		}
+		filtered = append(filtered, migration)
+	}
+	return filtered
+}
+
+// limitMigrations returns at most limit migrations; a limit of zero returns all
+func limitMigrations(migrations []models.Migration, limit int) []models.Migration {
+	if limit > 0 && len(migrations) > limit {
+		return migrations[:limit]
+	}
+	return migrations
+}
+
+// listGEIMigrations retrieves migrations using the new GEI API. Migrations are
+// listed newest first, so listing stops at the first migration created before
+// opts.Since. Once a page reaches the migrations of the listing's snapshot, which
+// have all finished, the remaining pages are taken from the snapshot instead.
+// The migrations are returned oldest first.
+func (c *githubClient) listGEIMigrations(ctx context.Context, opts models.ListOptions) ([]models.Migration, error) {
+	org := opts.Organization
+
 	var query struct {
 		Organization struct {
 			RepositoryMigrations struct {
@@ -80,26 +198,41 @@ func (c *githubClient) listGEIMigrations(ctx context.Context, org string) ([]mod
 					HasNextPage githubv4.Boolean
 				}
 				Edges []struct {
-					Node struct {
-						Id              string
-						CreatedAt       string
-						FailureReason   string
-						RepositoryName  string
-						State           string
-						MigrationLogUrl string
-					}
+					Node repositoryMigrationNode
 				}
-			} `graphql:"repositoryMigrations(first: $first, after: $after)"`
+			} `graphql:"repositoryMigrations(first: $first, after: $after, state: $state, repositoryName: $repositoryName, orderBy: {field: CREATED_AT, direction: DESC})"`
 		} `graphql:"organization(login: $orgName)"`
 	}
 
 	variables := map[string]interface{}{
-		"orgName": githubv4.String(org),
-		"first":   githubv4.Int(100),
-		"after":   (*githubv4.String)(nil),
+		"orgName":        githubv4.String(org),
+		"first":          githubv4.Int(100),
+		"after":          (*githubv4.String)(nil),
+		"state":          (*githubv4.MigrationState)(nil),
+		"repositoryName": (*githubv4.String)(nil),
+	}
+	if opts.State != "" {
+		state := githubv4.MigrationState(opts.State)
+		variables["state"] = &state
+	}
+	if opts.RepositoryName != "" {
+		variables["repositoryName"] = githubv4.NewString(githubv4.String(opts.RepositoryName))
+	}
+
+	// A limited or windowed listing is not complete, so it neither uses nor updates
+	// a snapshot; a window already stops listing at its start. Neither does a listing
+	// by state, which leaves out running migrations that may enter the state below
+	// the watermark.
+	now := time.Now()
+	key := snapshotKey(opts)
+	complete := opts.Limit == 0 && opts.Since.IsZero() && opts.Until.IsZero() && opts.State == ""
+	var snapshot *geiSnapshot
+	if complete {
+		snapshot = c.snapshots.get(key, now)
 	}
 
 	var migrations []models.Migration
+	done := false
 
 	for {
 		if err := c.graphqlClient.Query(ctx, &query, variables); err != nil {
@@ -111,68 +244,184 @@ func (c *githubClient) listGEIMigrations(ctx context.Context, org string) ([]mod
 		}
 
 		for _, edge := range query.Organization.RepositoryMigrations.Edges {
-			createdAt, err := time.Parse(time.RFC3339, edge.Node.CreatedAt)
-			if err != nil {
-				log.Printf("Failed to parse created_at time %s: %v", edge.Node.CreatedAt, err)
-				createdAt = time.Time{}
-			}
+			migration := edge.Node.toMigration()
 
-			migration := models.Migration{
-				ID:              edge.Node.Id,
-				RepositoryName:  edge.Node.RepositoryName,
-				State:           models.State(edge.Node.State),
-				CreatedAt:       createdAt,
-				FailureReason:   edge.Node.FailureReason,
-				MigrationLogURL: edge.Node.MigrationLogUrl,
+			// Every following migration is older still
+			if !opts.Since.IsZero() && migration.CreatedAt.Before(opts.Since) {
+				done = true
+				break
+			}
+			if !opts.Until.IsZero() && !migration.CreatedAt.Before(opts.Until) {
+				continue
 			}
 
 			migrations = append(migrations, migration)
+
+			// This and every older migration is in the snapshot
+			if snapshot != nil && !migration.CreatedAt.After(snapshot.watermark) {
+				done = true
+			}
 		}
 
-		if !query.Organization.RepositoryMigrations.PageInfo.HasNextPage {
+		if done || !bool(query.Organization.RepositoryMigrations.PageInfo.HasNextPage) ||
+			(opts.Limit > 0 && len(migrations) >= opts.Limit) {
 			break
 		}
 		variables["after"] = githubv4.NewString(query.Organization.RepositoryMigrations.PageInfo.EndCursor)
 	}
 
+	if !complete {
+		migrations = limitMigrations(migrations, opts.Limit)
+		sortByCreatedAt(migrations)
+		return migrations, nil
+	}
+
+	listedAt := now
+	if snapshot != nil {
+		migrations = snapshot.merge(migrations)
+		listedAt = snapshot.listedAt
+	} else {
+		sortByCreatedAt(migrations)
+	}
+	c.snapshots.update(key, migrations, listedAt)
+
 	return migrations, nil
 }
 
-// listLegacyMigrations retrieves migrations using the legacy migration API
-func (c *githubClient) listLegacyMigrations(ctx context.Context, org string) ([]models.Migration, error) {
-	var migrationStatusQuery struct {
-		Organization struct {
-			Migration struct {
-				Guid                githubv4.String
-				Id                  githubv4.ID
-				State               githubv4.String
-				UploadUrl           githubv4.String
-				MigratableResources struct {
-					Nodes []struct {
-						TargetUrl githubv4.String
-						ModelName githubv4.String
-					}
-					PageInfo struct {
-						HasNextPage githubv4.Boolean
-						EndCursor   githubv4.String
-					}
-				} `graphql:"migratableResources(first: $first, after: $after)"`
-			} `graphql:"migration(guid: $guid)"`
-		} `graphql:"organization(login: $orgName)"`
+// GetMigration implements GitHubClient.GetMigration
+func (c *githubClient) GetMigration(ctx context.Context, id string) (models.Migration, error) {
+	var query struct {
+		Node struct {
+			RepositoryMigration repositoryMigrationNode `graphql:"... on RepositoryMigration"`
+		} `graphql:"node(id: $id)"`
 	}
 
-	opt := &github.ListOptions{PerPage: 100}
 	variables := map[string]interface{}{
-		"orgName": githubv4.String(org),
-		"first":   githubv4.Int(100),
-		"after":   (*githubv4.String)(nil),
-		"guid":    githubv4.String(""),
+		"id": githubv4.ID(id),
+	}
+
+	if err := c.graphqlClient.Query(ctx, &query, variables); err != nil {
+		return models.Migration{}, &APIError{
+			StatusCode: 0,
+			Message:    fmt.Sprintf("failed to query migration %s", id),
+			Err:        err,
+		}
+	}
+	if query.Node.RepositoryMigration.Id == "" {
+		return models.Migration{}, &APIError{Message: fmt.Sprintf("migration %s not found", id)}
+	}
+
+	return query.Node.RepositoryMigration.toMigration(), nil
+}
+
+// repositoryMigrationNode holds the fields queried for a GEI repository migration
+type repositoryMigrationNode struct {
+	Id              string
+	DatabaseId      string
+	CreatedAt       string
+	FailureReason   string
+	RepositoryName  string
+	State           string
+	MigrationLogUrl string
+	SourceUrl       string
+	WarningsCount   int
+	ContinueOnError bool
+	MigrationSource struct {
+		Id   string
+		Name string
+		Type string
+		Url  string
+	}
+}
+
+// toMigration converts the node into a migration
+func (n repositoryMigrationNode) toMigration() models.Migration {
+	createdAt, err := time.Parse(time.RFC3339, n.CreatedAt)
+	if err != nil {
+		log.Printf("Failed to parse created_at time %s: %v", n.CreatedAt, err)
+		createdAt = time.Time{}
+	}
+
+	return models.Migration{
+		ID:              n.Id,
+		RepositoryName:  n.RepositoryName,
+		State:           models.State(n.State),
+		CreatedAt:       createdAt,
+		FailureReason:   n.FailureReason,
+		MigrationLogURL: n.MigrationLogUrl,
+		DatabaseID:      n.DatabaseId,
+		SourceURL:       n.SourceUrl,
+		WarningsCount:   n.WarningsCount,
+		ContinueOnError: n.ContinueOnError,
+		MigrationSource: models.MigrationSource{
+			ID:   n.MigrationSource.Id,
+			Name: n.MigrationSource.Name,
+			Type: n.MigrationSource.Type,
+			URL:  n.MigrationSource.Url,
+		},
+	}
+}
+
+// listLegacyMigrations retrieves migrations using the legacy migration API. The
+// migratable resources of the migrations are queried concurrently, at most
+// legacyConcurrency at a time, and returned in the order the migrations are listed.
+func (c *githubClient) listLegacyMigrations(ctx context.Context, org string, page int) ([]models.Migration, error) {
+	legacyMigrations, err := c.listLegacyExports(ctx, org, page)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]models.Migration, len(legacyMigrations))
+	ForEach(ctx, len(legacyMigrations), c.legacyConcurrency, func(ctx context.Context, i int) {
+		migration := legacyMigrations[i]
+
+		// The resources of a finished legacy migration no longer change
+		finished := isLegacyFinished(legacyState(migration))
+		if rows, ok := c.legacy.get(*migration.GUID); ok && finished {
+			results[i] = rows
+			return
+		}
+
+		rows, err := c.legacyMigrationRows(ctx, org, migration)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Error executing Migration Status GraphQL query: %v", err)
+			}
+			return
+		}
+		results[i] = rows
+
+		if finished {
+			c.legacy.put(*migration.GUID, rows)
+		}
+	})
+
+	// A cancelled refresh must not look like an organization without migrations
+	if err := ctx.Err(); err != nil {
+		return nil, &APIError{
+			StatusCode: 0,
+			Message:    fmt.Sprintf("failed to query legacy migrations for org %s", org),
+			Err:        err,
+		}
 	}
 
 	var migrations []models.Migration
+	for _, rows := range results {
+		migrations = append(migrations, rows...)
+	}
+
+	return migrations, nil
+}
+
+// listLegacyExports lists the legacy migrations of the organization that have a
+// GUID, from every page or only the given one
+func (c *githubClient) listLegacyExports(ctx context.Context, org string, page int) ([]*github.Migration, error) {
+	opt := &github.ListOptions{PerPage: 100, Page: page}
+
+	var legacyMigrations []*github.Migration
 
 	for {
-		legacyMigrations, resp, err := c.restClient.Migrations.ListMigrations(ctx, org, opt)
+		listed, resp, err := c.restClient.Migrations.ListMigrations(ctx, org, opt)
 		if err != nil {
 			return nil, &APIError{
 				StatusCode: 0,
@@ -181,60 +430,140 @@ func (c *githubClient) listLegacyMigrations(ctx context.Context, org string) ([]
 			}
 		}
 
-		for _, migration := range legacyMigrations {
-			if migration.GUID == nil {
-				continue
+		for _, migration := range listed {
+			if migration.GUID != nil {
+				legacyMigrations = append(legacyMigrations, migration)
 			}
+		}
 
-			variables["guid"] = githubv4.String(*migration.GUID)
-			if err := c.graphqlClient.Query(ctx, &migrationStatusQuery, variables); err != nil {
-				log.Printf("Error executing Migration Status GraphQL query: %v", err)
-				continue
-			}
+		if resp.NextPage == 0 || page > 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
 
-			for _, resource := range migrationStatusQuery.Organization.Migration.MigratableResources.Nodes {
-				if resource.ModelName == "repository" {
-					createdAt := time.Time{}
-					if migration.CreatedAt != nil {
-						if parsed, err := time.Parse(time.RFC3339, *migration.CreatedAt); err == nil {
-							createdAt = parsed
-						}
-					}
-
-					migrationURL := ""
-					if migration.URL != nil {
-						migrationURL = *migration.URL
-					}
-
-					state := ""
-					if migration.State != nil {
-						state = strings.ToUpper(*migration.State)
-					}
-
-					m := models.Migration{
-						ID:              *migration.GUID,
-						RepositoryName:  string(resource.TargetUrl),
-						State:           models.State(state),
-						CreatedAt:       createdAt,
-						FailureReason:   "Unavailable for legacy migrations",
-						MigrationLogURL: migrationURL,
-					}
-
-					migrations = append(migrations, m)
-				}
-			}
+	return legacyMigrations, nil
+}
 
-			if !migrationStatusQuery.Organization.Migration.MigratableResources.PageInfo.HasNextPage {
-				continue
-			}
-			variables["after"] = githubv4.NewString(migrationStatusQuery.Organization.Migration.MigratableResources.PageInfo.EndCursor)
+// legacyMigrationRows queries a legacy migration's resources and returns one
+// migration per repository it contains
+func (c *githubClient) legacyMigrationRows(ctx context.Context, org string, migration *github.Migration) ([]models.Migration, error) {
+	resources, err := c.listMigratableResources(ctx, org, *migration.GUID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := time.Time{}
+	if migration.CreatedAt != nil {
+		if parsed, err := time.Parse(time.RFC3339, *migration.CreatedAt); err == nil {
+			createdAt = parsed
 		}
+	}
 
-		if resp.NextPage == 0 {
-			break
+	migrationURL := ""
+	if migration.URL != nil {
+		migrationURL = *migration.URL
+	}
+
+	// Every repository row of the migration carries the breakdown of all its resources
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.ModelName]++
+	}
+
+	var rows []models.Migration
+	for _, resource := range resources {
+		if resource.ModelName != "repository" {
+			continue
 		}
-		opt.Page = resp.NextPage
+
+		rows = append(rows, models.Migration{
+			ID:              *migration.GUID,
+			RepositoryName:  resource.TargetUrl,
+			State:           legacyState(migration),
+			CreatedAt:       createdAt,
+			FailureReason:   "Unavailable for legacy migrations",
+			MigrationLogURL: migrationURL,
+			ResourceCounts:  counts,
+		})
 	}
 
-	return migrations, nil
+	return rows, nil
+}
+
+// legacyState returns the state of a legacy migration
+func legacyState(migration *github.Migration) models.State {
+	if migration.State == nil {
+		return ""
+	}
+	return models.State(strings.ToUpper(*migration.State))
+}
+
+// legacyStateExported is the state of a legacy migration whose export archive is ready
+const legacyStateExported models.State = "EXPORTED"
+
+// isLegacyFinished returns true for legacy migration states that can no longer change,
+// including exported, which the shared states do not cover
+func isLegacyFinished(state models.State) bool {
+	return state == legacyStateExported || state.IsSucceeded() || state.IsFailed()
+}
+
+// migratableResource is a resource included in a legacy migration
+type migratableResource struct {
+	TargetUrl string
+	ModelName string
+}
+
+// listMigratableResources retrieves every migratable resource of a legacy migration,
+// following the resource pages from the first one
+func (c *githubClient) listMigratableResources(ctx context.Context, org, guid string) ([]migratableResource, error) {
+	variables := map[string]interface{}{
+		"orgName": githubv4.String(org),
+		"guid":    githubv4.String(guid),
+		"first":   githubv4.Int(100),
+		"after":   (*githubv4.String)(nil),
+	}
+
+	var resources []migratableResource
+
+	for {
+		// A fresh query value per page, so nothing carries over from the previous page
+		var query struct {
+			Organization struct {
+				Migration struct {
+					Guid                githubv4.String
+					Id                  githubv4.ID
+					State               githubv4.String
+					UploadUrl           githubv4.String
+					MigratableResources struct {
+						Nodes    []migratableResource
+						PageInfo struct {
+							HasNextPage githubv4.Boolean
+							EndCursor   githubv4.String
+						}
+					} `graphql:"migratableResources(first: $first, after: $after)"`
+				} `graphql:"migration(guid: $guid)"`
+			} `graphql:"organization(login: $orgName)"`
+		}
+
+		if err := c.graphqlClient.Query(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query migratable resources of legacy migration %s: %w", guid, err)
+		}
+
+		page := query.Organization.Migration.MigratableResources
+		resources = append(resources, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage {
+			return resources, nil
+		}
+
+		// Stop rather than request the same page forever if the cursor does not advance
+		if page.PageInfo.EndCursor == "" {
+			return nil, fmt.Errorf("legacy migration %s reported more migratable resources without a cursor", guid)
+		}
+		if after, ok := variables["after"].(*githubv4.String); ok && after != nil && *after == page.PageInfo.EndCursor {
+			return nil, fmt.Errorf("legacy migration %s returned the same migratable resources cursor twice", guid)
+		}
+		variables["after"] = githubv4.NewString(page.PageInfo.EndCursor)
+	}
 }
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// graphQLRequest is a GraphQL request received by the fake GitHub server
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// fakeGitHub is a GitHub Enterprise Server stand-in that answers GraphQL queries
+// with graphql and REST requests with the JSON in rest, keyed by path
+type fakeGitHub struct {
+	*httptest.Server
+
+	graphql func(req graphQLRequest) string
+	rest    map[string]string
+
+	mu       sync.Mutex
+	paths    []string
+	queries  []graphQLRequest
+	features []string
+}
+
+// newFakeGitHub starts a fake GitHub server that is closed when the test ends
+func newFakeGitHub(t *testing.T, graphql func(req graphQLRequest) string, rest map[string]string) *fakeGitHub {
+	t.Helper()
+
+	f := &fakeGitHub{graphql: graphql, rest: rest}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGitHub) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.paths = append(f.paths, r.URL.Path)
+	f.features = append(f.features, r.Header.Get("Graphql-Features"))
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path == "/api/graphql" {
+		var req graphQLRequest
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.queries = append(f.queries, req)
+		f.mu.Unlock()
+
+		fmt.Fprint(w, f.graphql(req))
+		return
+	}
+
+	if body, ok := f.rest[r.URL.Path]; ok {
+		fmt.Fprint(w, body)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// hit returns true if a request was made to a path starting with prefix
+func (f *fakeGitHub) hit(prefix string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, path := range f.paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTestClient creates a client for the fake server
+func newTestClient(t *testing.T, server *fakeGitHub, isLegacy bool) *githubClient {
+	t.Helper()
+
+	client, err := NewGitHubClient("token", isLegacy, WithHostname(server.URL))
+	if err != nil {
+		t.Fatalf("NewGitHubClient: %v", err)
+	}
+	return client.(*githubClient)
+}
+
+func TestNewGitHubClientUsesGHESEndpoints(t *testing.T) {
+	pages := legacyGraphQL(map[string]map[string]resourcePage{
+		"g1": {"": {resources: [][2]string{{"https://ghes/org/r1", "repository"}}}},
+		"g2": {"": {resources: [][2]string{{"https://ghes/org/r2", "repository"}}}},
+	})
+	graphql := func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "repositoryMigrations") {
+			return `{"data":{"organization":{"repositoryMigrations":{"pageInfo":{"hasNextPage":false,"endCursor":""},"edges":[]}}}}`
+		}
+		return pages(req)
+	}
+
+	for _, isLegacy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("legacy=%t", isLegacy), func(t *testing.T) {
+			server := newFakeGitHub(t, graphql, map[string]string{"/api/v3/orgs/org/migrations": legacyExports})
+			client := newTestClient(t, server, isLegacy)
+
+			if _, err := client.ListMigrations(context.Background(), models.ListOptions{Organization: "org"}, isLegacy); err != nil {
+				t.Fatalf("ListMigrations: %v", err)
+			}
+			if _, err := client.ListMigrations(context.Background(), models.ListOptions{Organization: "org"}, true); err != nil {
+				t.Fatalf("ListMigrations of legacy migrations: %v", err)
+			}
+
+			if !server.hit("/api/graphql") {
+				t.Errorf("no request to /api/graphql, got %q", server.paths)
+			}
+			if !server.hit("/api/v3/") {
+				t.Errorf("no request to /api/v3/, got %q", server.paths)
+			}
+			for _, path := range server.paths {
+				if path != "/api/graphql" && !strings.HasPrefix(path, "/api/v3/") {
+					t.Errorf("request to %s outside of the GHES API", path)
+				}
+			}
+
+			// Only the legacy client asks for the legacy migration GraphQL schema
+			want := ""
+			if isLegacy {
+				want = "gh_migrator_import_to_dotcom"
+			}
+			for _, features := range server.features {
+				if features != want {
+					t.Errorf("Graphql-Features = %q, want %q", features, want)
+				}
+			}
+		})
+	}
+}
+
+// resourcePage is one page of a legacy migration's migratable resources
+type resourcePage struct {
+	resources   [][2]string
+	hasNextPage bool
+	endCursor   string
+}
+
+// migratableResourcesResponse encodes a page as a migration(guid) query response
+func migratableResourcesResponse(guid string, page resourcePage) string {
+	var nodes []string
+	for _, resource := range page.resources {
+		nodes = append(nodes, fmt.Sprintf(`{"targetUrl":%q,"modelName":%q}`, resource[0], resource[1]))
+	}
+	return fmt.Sprintf(`{"data":{"organization":{"migration":{"guid":%q,"id":"M_%s","state":"EXPORTED","uploadUrl":"",`+
+		`"migratableResources":{"nodes":[%s],"pageInfo":{"hasNextPage":%t,"endCursor":%q}}}}}}`,
+		guid, guid, strings.Join(nodes, ","), page.hasNextPage, page.endCursor)
+}
+
+// legacyGraphQL answers migratableResources queries from pages keyed by GUID and
+// the after cursor, where the first page has the cursor ""
+func legacyGraphQL(pages map[string]map[string]resourcePage) func(req graphQLRequest) string {
+	return func(req graphQLRequest) string {
+		guid, _ := req.Variables["guid"].(string)
+		after, _ := req.Variables["after"].(string)
+		page, ok := pages[guid][after]
+		if !ok {
+			return `{"errors":[{"message":"unexpected cursor"}]}`
+		}
+		return migratableResourcesResponse(guid, page)
+	}
+}
+
+const legacyExports = `[
+	{"id":1,"guid":"g1","state":"exported","created_at":"2024-05-01T10:00:00Z"},
+	{"id":2,"guid":"g2","state":"failed","created_at":"2024-05-02T10:00:00Z"}
+]`
+
+func TestListLegacyMigrationsPagesMigratableResources(t *testing.T) {
+	server := newFakeGitHub(t, legacyGraphQL(map[string]map[string]resourcePage{
+		"g1": {
+			"": {
+				resources:   [][2]string{{"https://ghes/org/r1", "repository"}, {"https://ghes/org/r1/projects/1", "project"}},
+				hasNextPage: true, endCursor: "g1p2",
+			},
+			"g1p2": {
+				resources:   [][2]string{{"https://ghes/org/r2", "repository"}, {"https://ghes/org/team", "team"}},
+				hasNextPage: true, endCursor: "g1p3",
+			},
+			"g1p3": {
+				resources: [][2]string{{"https://ghes/org/r3", "repository"}},
+			},
+		},
+		"g2": {
+			"": {
+				resources:   [][2]string{{"https://ghes/org/r4", "repository"}},
+				hasNextPage: true, endCursor: "g2p2",
+			},
+			"g2p2": {
+				resources: [][2]string{{"https://ghes/org/r5", "repository"}, {"https://ghes/org/user", "user"}},
+			},
+		},
+	}), map[string]string{"/api/v3/orgs/org/migrations": legacyExports})
+
+	client := newTestClient(t, server, true)
+	migrations, err := client.listLegacyMigrations(context.Background(), "org", 0)
+	if err != nil {
+		t.Fatalf("listLegacyMigrations: %v", err)
+	}
+
+	var repositories []string
+	for _, migration := range migrations {
+		repositories = append(repositories, migration.ID+" "+migration.RepositoryName)
+	}
+	want := []string{
+		"g1 https://ghes/org/r1", "g1 https://ghes/org/r2", "g1 https://ghes/org/r3",
+		"g2 https://ghes/org/r4", "g2 https://ghes/org/r5",
+	}
+	if strings.Join(repositories, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("repositories = %q, want %q", repositories, want)
+	}
+
+	if counts := migrations[0].ResourceCounts; counts["repository"] != 3 || counts["project"] != 1 || counts["team"] != 1 {
+		t.Errorf("g1 resource counts = %v, want 3 repositories, 1 project and 1 team", counts)
+	}
+	if counts := migrations[3].ResourceCounts; counts["repository"] != 2 || counts["user"] != 1 {
+		t.Errorf("g2 resource counts = %v, want 2 repositories and 1 user", counts)
+	}
+
+	// The first query of every migration starts from the first page
+	firstAfter := make(map[string]any)
+	seen := make(map[string]bool)
+	for _, query := range server.queries {
+		guid, _ := query.Variables["guid"].(string)
+		if seen[guid] {
+			continue
+		}
+		seen[guid] = true
+		after, ok := query.Variables["after"]
+		if !ok {
+			t.Errorf("query for %s has no after variable", guid)
+		}
+		firstAfter[guid] = after
+	}
+	for _, guid := range []string{"g1", "g2"} {
+		if !seen[guid] {
+			t.Errorf("no migratableResources query for %s", guid)
+		} else if firstAfter[guid] != nil {
+			t.Errorf("first query for %s has after = %v, want null", guid, firstAfter[guid])
+		}
+	}
+	if len(server.queries) != 5 {
+		t.Errorf("made %d GraphQL queries, want 5", len(server.queries))
+	}
+}
+
+func TestListMigratableResourcesCursorErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages map[string]resourcePage
+		want  string
+	}{
+		{
+			name: "empty cursor",
+			pages: map[string]resourcePage{
+				"": {resources: [][2]string{{"https://ghes/org/r1", "repository"}}, hasNextPage: true},
+			},
+			want: "without a cursor",
+		},
+		{
+			name: "repeated cursor",
+			pages: map[string]resourcePage{
+				"":   {resources: [][2]string{{"https://ghes/org/r1", "repository"}}, hasNextPage: true, endCursor: "p2"},
+				"p2": {resources: [][2]string{{"https://ghes/org/r2", "repository"}}, hasNextPage: true, endCursor: "p2"},
+			},
+			want: "same migratable resources cursor twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeGitHub(t, legacyGraphQL(map[string]map[string]resourcePage{"g1": tt.pages}), nil)
+			client := newTestClient(t, server, true)
+
+			resources, err := client.listMigratableResources(context.Background(), "org", "g1")
+			if err == nil {
+				t.Fatalf("listMigratableResources returned %v, want an error", resources)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestListLegacyMigrationsCachesFinishedMigrations(t *testing.T) {
+	server := newFakeGitHub(t, legacyGraphQL(map[string]map[string]resourcePage{
+		"g1": {"": {resources: [][2]string{{"https://ghes/org/r1", "repository"}}}},
+		"g2": {"": {resources: [][2]string{{"https://ghes/org/r2", "repository"}}}},
+		"g3": {"": {resources: [][2]string{{"https://ghes/org/r3", "repository"}}}},
+	}), map[string]string{"/api/v3/orgs/org/migrations": `[
+		{"id":1,"guid":"g1","state":"exported","created_at":"2024-05-01T10:00:00Z"},
+		{"id":2,"guid":"g2","state":"failed","created_at":"2024-05-02T10:00:00Z"},
+		{"id":3,"guid":"g3","state":"exporting","created_at":"2024-05-03T10:00:00Z"}
+	]`})
+	client := newTestClient(t, server, true)
+
+	for i := 0; i < 2; i++ {
+		migrations, err := client.listLegacyMigrations(context.Background(), "org", 0)
+		if err != nil {
+			t.Fatalf("listLegacyMigrations: %v", err)
+		}
+		if len(migrations) != 3 {
+			t.Fatalf("listing %d returned %d migrations, want 3", i+1, len(migrations))
+		}
+	}
+
+	// Exported and failed migrations are only queried once, running ones every time
+	queried := make(map[string]int)
+	for _, query := range server.queries {
+		guid, _ := query.Variables["guid"].(string)
+		queried[guid]++
+	}
+	want := map[string]int{"g1": 1, "g2": 1, "g3": 2}
+	for guid, count := range want {
+		if queried[guid] != count {
+			t.Errorf("%s was queried %d times, want %d", guid, queried[guid], count)
+		}
+	}
+}
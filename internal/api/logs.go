@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// logDownloadTimeout bounds how long downloading a single migration log may take
+const logDownloadTimeout = 5 * time.Minute
+
+// DownloadMigrationLog implements GitHubClient.DownloadMigrationLog.
+//
+// The token is only sent when the log URL points at the GitHub instance itself.
+// Redirects are followed, and the token is not forwarded when a redirect leads to
+// another host such as a storage service serving a pre-signed URL.
+func (c *githubClient) DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid migration log URL %q: %w", logURL, err)
+	}
+	if c.isGitHubHost(req.URL) {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	client := &http.Client{Timeout: logDownloadTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &APIError{Message: "failed to download migration log", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("failed to download migration log: unexpected status %s", resp.Status),
+		}
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return &APIError{Message: "failed to download migration log", Err: err}
+	}
+	return nil
+}
+
+// isGitHubHost returns true if the URL points at the GitHub instance or its API
+func (c *githubClient) isGitHubHost(u *url.URL) bool {
+	host := strings.ToLower(u.Host)
+	for _, githubHost := range c.hosts {
+		if host == githubHost {
+			return true
+		}
+	}
+	return false
+}
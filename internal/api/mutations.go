@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/shurcooL/githubv4"
+)
+
+// StartMigrationInput describes a GEI repository migration to start
+type StartMigrationInput struct {
+	// Organization is the login of the organization that will own the repository
+	Organization    string
+	RepositoryName  string
+	SourceID        string
+	SourceURL       string
+	ContinueOnError bool
+
+	// SourceToken grants access to the source repository. The client's own token
+	// is used when it is empty.
+	SourceToken string
+}
+
+// StartRepositoryMigration implements GitHubClient.StartRepositoryMigration
+func (c *githubClient) StartRepositoryMigration(ctx context.Context, input StartMigrationInput) (models.Migration, error) {
+	ownerID, err := c.organizationID(ctx, input.Organization)
+	if err != nil {
+		return models.Migration{}, err
+	}
+
+	sourceURL, err := url.Parse(input.SourceURL)
+	if err != nil {
+		return models.Migration{}, fmt.Errorf("invalid source URL %q: %w", input.SourceURL, err)
+	}
+
+	sourceToken := input.SourceToken
+	if sourceToken == "" {
+		sourceToken = c.token
+	}
+
+	var mutation struct {
+		StartRepositoryMigration struct {
+			RepositoryMigration repositoryMigrationNode
+		} `graphql:"startRepositoryMigration(input: $input)"`
+	}
+
+	mutationInput := githubv4.StartRepositoryMigrationInput{
+		SourceID:            githubv4.ID(input.SourceID),
+		OwnerID:             githubv4.ID(ownerID),
+		RepositoryName:      githubv4.String(input.RepositoryName),
+		SourceRepositoryURL: githubv4.NewURI(githubv4.URI{URL: sourceURL}),
+		ContinueOnError:     githubv4.NewBoolean(githubv4.Boolean(input.ContinueOnError)),
+		AccessToken:         githubv4.NewString(githubv4.String(sourceToken)),
+		GitHubPat:           githubv4.NewString(githubv4.String(c.token)),
+	}
+
+	if err := c.graphqlClient.Mutate(ctx, &mutation, mutationInput, nil); err != nil {
+		return models.Migration{}, &APIError{
+			StatusCode: 0,
+			Message:    fmt.Sprintf("failed to start migration of %s/%s", input.Organization, input.RepositoryName),
+			Err:        err,
+		}
+	}
+
+	migration := mutation.StartRepositoryMigration.RepositoryMigration.toMigration()
+	migration.Organization = input.Organization
+	return migration, nil
+}
+
+// organizationID looks up the GraphQL node ID of an organization
+func (c *githubClient) organizationID(ctx context.Context, org string) (string, error) {
+	var query struct {
+		Organization struct {
+			Id string
+		} `graphql:"organization(login: $orgName)"`
+	}
+
+	variables := map[string]interface{}{
+		"orgName": githubv4.String(org),
+	}
+
+	if err := c.graphqlClient.Query(ctx, &query, variables); err != nil {
+		return "", &APIError{
+			StatusCode: 0,
+			Message:    fmt.Sprintf("failed to look up organization %s", org),
+			Err:        err,
+		}
+	}
+	if query.Organization.Id == "" {
+		return "", &APIError{Message: fmt.Sprintf("organization %s not found", org)}
+	}
+
+	return query.Organization.Id, nil
+}
+
+// AbortRepositoryMigrationInput is the input of the abortRepositoryMigration
+// mutation, which githubv4 does not define
+type AbortRepositoryMigrationInput struct {
+	MigrationID githubv4.ID `json:"migrationId"`
+}
+
+// AbortRepositoryMigration implements GitHubClient.AbortRepositoryMigration
+func (c *githubClient) AbortRepositoryMigration(ctx context.Context, migrationID string) error {
+	var mutation struct {
+		AbortRepositoryMigration struct {
+			Success bool
+		} `graphql:"abortRepositoryMigration(input: $input)"`
+	}
+
+	input := AbortRepositoryMigrationInput{MigrationID: githubv4.ID(migrationID)}
+	if err := c.graphqlClient.Mutate(ctx, &mutation, input, nil); err != nil {
+		return &APIError{
+			StatusCode: 0,
+			Message:    fmt.Sprintf("failed to abort migration %s", migrationID),
+			Err:        err,
+		}
+	}
+	if !mutation.AbortRepositoryMigration.Success {
+		return &APIError{Message: fmt.Sprintf("migration %s was not aborted", migrationID)}
+	}
+
+	return nil
+}
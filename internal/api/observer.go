@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// API identifiers reported to request observers
+const (
+	APIREST    = "rest"
+	APIGraphQL = "graphql"
+)
+
+// RequestInfo describes a single HTTP request made to the GitHub API
+type RequestInfo struct {
+	// API is APIREST or APIGraphQL
+	API        string
+	Method     string
+	StatusCode int
+	Duration   time.Duration
+	// Err is set when the request failed before a response was received
+	Err error
+}
+
+// Failed returns true if the request errored or GitHub returned an error status
+func (r RequestInfo) Failed() bool {
+	return r.Err != nil || r.StatusCode >= http.StatusBadRequest
+}
+
+// RequestObserver is called after every HTTP request made to the GitHub API
+type RequestObserver func(RequestInfo)
+
+// WithRequestObserver reports the outcome and latency of every API request, e.g.
+// to export metrics. Requests retried by the rate limiter are reported individually.
+func WithRequestObserver(observer RequestObserver) ClientOption {
+	return func(o *clientOptions) {
+		o.observers = append(o.observers, observer)
+	}
+}
+
+// observeTransport wraps a transport so every request is reported to the observers
+func observeTransport(next http.RoundTripper, graphqlURL string, observers []RequestObserver) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		info := RequestInfo{
+			API:    APIREST,
+			Method: req.Method,
+		}
+		if strings.HasPrefix(req.URL.String(), graphqlURL) {
+			info.API = APIGraphQL
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		info.Duration = time.Since(start)
+		info.Err = err
+		if resp != nil {
+			info.StatusCode = resp.StatusCode
+		}
+
+		for _, observer := range observers {
+			observer(info)
+		}
+
+		return resp, err
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of concurrent API requests used when no limit is configured
+const DefaultConcurrency = 4
+
+// ForEach calls fn for every index in [0, n) with at most limit calls running at
+// once, and returns once all calls have finished. Indexes not yet started when the
+// context is cancelled are skipped. A limit below one uses DefaultConcurrency.
+func ForEach(ctx context.Context, n, limit int, fn func(ctx context.Context, i int)) {
+	if limit < 1 {
+		limit = DefaultConcurrency
+	}
+
+	semaphore := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
+}
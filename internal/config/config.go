@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -9,18 +10,56 @@ import (
 // Config represents the application configuration
 type Config struct {
 	GitHub struct {
-		Token        string `mapstructure:"token"`
-		Organization string `mapstructure:"organization"`
+		Token         string   `mapstructure:"token"`
+		Organization  string   `mapstructure:"organization"`
+		Organizations []string `mapstructure:"organizations"`
+		Enterprise    string   `mapstructure:"enterprise"`
+		Hostname      string   `mapstructure:"hostname"`
 	} `mapstructure:"github"`
 
 	Migration struct {
-		IsLegacy bool `mapstructure:"is_legacy"`
+		IsLegacy          bool   `mapstructure:"is_legacy"`
+		SourceToken       string `mapstructure:"source_token"`
+		LegacyConcurrency int    `mapstructure:"legacy_concurrency"`
 	} `mapstructure:"migration"`
 
 	Output struct {
 		Format string `mapstructure:"format"`
 		Quiet  bool   `mapstructure:"quiet"`
 	} `mapstructure:"output"`
+
+	History struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Path    string `mapstructure:"path"`
+	} `mapstructure:"history"`
+
+	Metrics struct {
+		Address string `mapstructure:"address"`
+	} `mapstructure:"metrics"`
+
+	Logs struct {
+		Dir string `mapstructure:"dir"`
+	} `mapstructure:"logs"`
+
+	Manifest struct {
+		Path string `mapstructure:"path"`
+	} `mapstructure:"manifest"`
+
+	Notifications struct {
+		Bell    bool   `mapstructure:"bell"`
+		Desktop string `mapstructure:"desktop"`
+		Command string `mapstructure:"command"`
+
+		Webhooks []WebhookConfig `mapstructure:"webhooks"`
+	} `mapstructure:"notifications"`
+}
+
+// WebhookConfig represents an outbound webhook that receives migration events
+type WebhookConfig struct {
+	URL    string   `mapstructure:"url"`
+	Secret string   `mapstructure:"secret"`
+	Format string   `mapstructure:"format"`
+	Events []string `mapstructure:"events"`
 }
 
 // LoadConfig loads configuration from environment variables and config files
@@ -30,6 +69,12 @@ func LoadConfig() (*Config, error) {
 	viper.AddConfigPath("$HOME/.gh-migration-monitor")
 	viper.AddConfigPath(".")
 
+	// Defaults
+	viper.SetDefault("github.hostname", "github.com")
+	viper.SetDefault("output.format", "table")
+	viper.SetDefault("history.enabled", true)
+	viper.SetDefault("metrics.address", ":9090")
+
 	// Environment variables
 	viper.SetEnvPrefix("GHMM")
 	viper.AutomaticEnv()
@@ -37,7 +82,22 @@ func LoadConfig() (*Config, error) {
 	// Bind specific environment variables
 	viper.BindEnv("github.token", "GHMM_GITHUB_TOKEN")
 	viper.BindEnv("github.organization", "GHMM_GITHUB_ORGANIZATION")
+	viper.BindEnv("github.organizations", "GHMM_GITHUB_ORGANIZATIONS")
+	viper.BindEnv("github.enterprise", "GHMM_GITHUB_ENTERPRISE")
+	viper.BindEnv("github.hostname", "GHMM_GITHUB_HOSTNAME")
 	viper.BindEnv("migration.is_legacy", "GHMM_ISLEGACY")
+	viper.BindEnv("migration.source_token", "GHMM_MIGRATION_SOURCE_TOKEN")
+	viper.BindEnv("migration.legacy_concurrency", "GHMM_MIGRATION_LEGACY_CONCURRENCY")
+	viper.BindEnv("output.format", "GHMM_OUTPUT_FORMAT")
+	viper.BindEnv("output.quiet", "GHMM_OUTPUT_QUIET")
+	viper.BindEnv("history.enabled", "GHMM_HISTORY_ENABLED")
+	viper.BindEnv("history.path", "GHMM_HISTORY_PATH")
+	viper.BindEnv("metrics.address", "GHMM_METRICS_ADDRESS")
+	viper.BindEnv("manifest.path", "GHMM_MANIFEST_PATH")
+	viper.BindEnv("logs.dir", "GHMM_LOGS_DIR")
+	viper.BindEnv("notifications.bell", "GHMM_NOTIFICATIONS_BELL")
+	viper.BindEnv("notifications.desktop", "GHMM_NOTIFICATIONS_DESKTOP")
+	viper.BindEnv("notifications.command", "GHMM_NOTIFICATIONS_COMMAND")
 
 	// Read configuration file if it exists
 	if err := viper.ReadInConfig(); err != nil {
@@ -54,10 +114,26 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// OrganizationNames returns the organizations to monitor, combining organization
+// and organizations without duplicates
+func (c *Config) OrganizationNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range append([]string{c.GitHub.Organization}, c.GitHub.Organizations...) {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	return names
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.GitHub.Organization == "" {
-		return fmt.Errorf("github organization is required")
+	if len(c.OrganizationNames()) == 0 && c.GitHub.Enterprise == "" {
+		return fmt.Errorf("github organization or enterprise is required")
 	}
 
 	if c.GitHub.Token == "" {
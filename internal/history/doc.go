@@ -0,0 +1,8 @@
+// Package history provides a persistent store of migration state transitions.
+//
+// Every time the migration monitor observes an organization's migrations, the
+// store compares them with the last state it recorded and appends any changes to
+// a JSON Lines file under ~/.gh-migration-monitor/. The recorded transitions
+// survive across sessions and can be used to calculate how long migrations spent
+// in each state and to audit a migration wave after the fact.
+package history
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// StateDuration describes how long a migration was observed in a single state.
+//
+// Durations are measured between observations, so their precision is bounded by
+// how often the organization was refreshed.
+type StateDuration struct {
+	Transition
+	// Duration is the time until the next recorded transition, or until now if the
+	// migration is still in a non-terminal state. It is zero for terminal states.
+	Duration time.Duration
+	// Ongoing is true if the migration was still in this state at the last observation
+	Ongoing bool
+}
+
+// Durations calculates how long each migration spent in every state it was observed in.
+// The transitions must be ordered oldest first, as returned by Store.Transitions.
+func Durations(transitions []Transition, now time.Time) []StateDuration {
+	durations := make([]StateDuration, len(transitions))
+	lastIndex := make(map[string]int)
+
+	for i, transition := range transitions {
+		durations[i] = StateDuration{Transition: transition}
+
+		if previous, ok := lastIndex[transition.key()]; ok {
+			durations[previous].Duration = transition.ObservedAt.Sub(transitions[previous].ObservedAt)
+		}
+		lastIndex[transition.key()] = i
+	}
+
+	// The latest state of each migration is still ongoing unless it is terminal
+	for _, i := range lastIndex {
+		if isTerminal(transitions[i].ToState) {
+			continue
+		}
+		durations[i].Duration = now.Sub(transitions[i].ObservedAt)
+		durations[i].Ongoing = true
+	}
+
+	return durations
+}
+
+// isTerminal returns true if a migration in this state will not change again
+func isTerminal(state models.State) bool {
+	return state.IsSucceeded() || state.IsFailed()
+}
@@ -0,0 +1,44 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+func TestDurations(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	transitions := []Transition{
+		{Organization: "org", MigrationID: "RM_1", RepositoryName: "api", ToState: models.StateQueued, ObservedAt: at(0)},
+		{Organization: "org", MigrationID: "RM_2", RepositoryName: "web", ToState: models.StateInProgress, ObservedAt: at(5)},
+		{Organization: "org", MigrationID: "RM_1", RepositoryName: "api", FromState: models.StateQueued, ToState: models.StateInProgress, ObservedAt: at(10)},
+		{Organization: "org", MigrationID: "RM_1", RepositoryName: "api", FromState: models.StateInProgress, ToState: models.StateSucceeded, ObservedAt: at(40)},
+	}
+
+	durations := Durations(transitions, at(60))
+
+	want := []struct {
+		duration time.Duration
+		ongoing  bool
+	}{
+		{10 * time.Minute, false}, // queued until it started
+		{55 * time.Minute, true},  // still in progress
+		{30 * time.Minute, false}, // in progress until it succeeded
+		{0, false},                // succeeded is terminal
+	}
+	if len(durations) != len(want) {
+		t.Fatalf("got %d durations, want %d", len(durations), len(want))
+	}
+	for i, w := range want {
+		if durations[i].Duration != w.duration || durations[i].Ongoing != w.ongoing {
+			t.Errorf("durations[%d] = %s (ongoing %t), want %s (ongoing %t)",
+				i, durations[i].Duration, durations[i].Ongoing, w.duration, w.ongoing)
+		}
+		if durations[i].Transition != transitions[i] {
+			t.Errorf("durations[%d] is for %+v, want %+v", i, durations[i].Transition, transitions[i])
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// DefaultFileName is the name of the history file inside the configuration directory
+const DefaultFileName = "history.jsonl"
+
+// Transition records a migration being observed in a new state
+type Transition struct {
+	Organization   string       `json:"organization"`
+	MigrationID    string       `json:"migration_id"`
+	RepositoryName string       `json:"repository_name"`
+	FromState      models.State `json:"from_state,omitempty"`
+	ToState        models.State `json:"to_state"`
+	ObservedAt     time.Time    `json:"observed_at"`
+}
+
+// IsFirstObservation returns true if the transition records the first time a migration was seen
+func (t Transition) IsFirstObservation() bool {
+	return t.FromState == ""
+}
+
+// key identifies a migration across observations. Legacy migrations share one
+// ID across several repositories, so the repository name is part of the key.
+func (t Transition) key() string {
+	return migrationKey(t.Organization, t.MigrationID, t.RepositoryName)
+}
+
+// migrationKey builds the key used to track the last known state of a migration
+func migrationKey(org, id, repository string) string {
+	return org + "\x00" + id + "\x00" + repository
+}
+
+// Store persists migration state transitions to an append-only JSON Lines file.
+//
+// Several processes, such as the dashboard and serve, may record to the same file.
+// Each one locks the file while recording and first reads the transitions the
+// others appended, so every transition is written once.
+type Store struct {
+	mu          sync.Mutex
+	path        string
+	file        *os.File
+	offset      int64
+	lastState   map[string]models.State
+	finishedAt  map[string]time.Time
+	transitions []Transition
+}
+
+// DefaultPath returns the default history file location, ~/.gh-migration-monitor/history.jsonl
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".gh-migration-monitor", DefaultFileName), nil
+}
+
+// Open opens the history file at path, creating it and its directory if needed,
+// and loads the transitions recorded in previous sessions
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	store := &Store{
+		path:       path,
+		lastState:  make(map[string]models.State),
+		finishedAt: make(map[string]time.Time),
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file %s: %w", path, err)
+	}
+	store.file = file
+
+	if err := store.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// load reads the transitions appended to the history file since it was last read
+func (s *Store) load() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read history file %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read history file %s: %w", s.path, err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A partially written line is read again once it is complete
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read history file %s: %w", s.path, err)
+		}
+		s.offset += int64(len(line))
+
+		var transition Transition
+		if err := json.Unmarshal(line, &transition); err != nil {
+			// Skip lines that were only partially written, e.g. after a crash
+			continue
+		}
+		s.transitions = append(s.transitions, transition)
+		s.lastState[transition.key()] = transition.ToState
+		s.recordFinish(transition)
+	}
+}
+
+// Path returns the location of the history file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Record compares the observed migrations with their last recorded state and
+// appends a transition for every migration that is new or has changed state.
+// It returns the transitions that were recorded.
+func (s *Store) Record(org string, migrations []models.Migration, observedAt time.Time) ([]Transition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil, fmt.Errorf("history store is closed")
+	}
+
+	// Compare with the transitions other processes recorded in the meantime
+	unlock, err := lockFile(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock history file %s: %w", s.path, err)
+	}
+	defer unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	var recorded []Transition
+	for _, migration := range migrations {
+		key := migrationKey(org, migration.ID, migration.RepositoryName)
+		previous, seen := s.lastState[key]
+		if seen && previous == migration.State {
+			continue
+		}
+
+		transition := Transition{
+			Organization:   org,
+			MigrationID:    migration.ID,
+			RepositoryName: migration.RepositoryName,
+			FromState:      previous,
+			ToState:        migration.State,
+			ObservedAt:     observedAt.UTC(),
+		}
+
+		line, err := json.Marshal(transition)
+		if err != nil {
+			return recorded, fmt.Errorf("failed to encode transition: %w", err)
+		}
+		n, err := s.file.Write(append(line, '\n'))
+		s.offset += int64(n)
+		if err != nil {
+			return recorded, fmt.Errorf("failed to write history file %s: %w", s.path, err)
+		}
+
+		s.lastState[key] = migration.State
+		s.recordFinish(transition)
+		s.transitions = append(s.transitions, transition)
+		recorded = append(recorded, transition)
+	}
+
+	return recorded, nil
+}
+
+// recordFinish remembers when a migration was first seen finished. A migration
+// that was already finished when it was first observed finished at an unknown time.
+func (s *Store) recordFinish(transition Transition) {
+	key := transition.key()
+	if _, ok := s.finishedAt[key]; ok || transition.IsFirstObservation() {
+		return
+	}
+	if transition.ToState.IsSucceeded() || transition.ToState.IsFailed() {
+		s.finishedAt[key] = transition.ObservedAt
+	}
+}
+
+// FinishedAt returns when a migration of the organization was first seen in a
+// succeeded or failed state, if it was observed before it finished
+func (s *Store) FinishedAt(org string, migration models.Migration) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finishedAt, ok := s.finishedAt[migrationKey(org, migration.ID, migration.RepositoryName)]
+	return finishedAt, ok
+}
+
+// Filter selects transitions when querying the store
+type Filter struct {
+	// Organizations matches any of the listed organizations; empty matches all
+	Organizations  []string
+	MigrationID    string
+	RepositoryName string
+	Since          time.Time
+}
+
+// matches checks if a transition matches every field set on the filter
+func (f Filter) matches(t Transition) bool {
+	return f.matchesOrganization(t.Organization) &&
+		(f.MigrationID == "" || f.MigrationID == t.MigrationID) &&
+		(f.RepositoryName == "" || f.RepositoryName == t.RepositoryName) &&
+		(f.Since.IsZero() || !t.ObservedAt.Before(f.Since))
+}
+
+// matchesOrganization checks if the organization is selected by the filter
+func (f Filter) matchesOrganization(org string) bool {
+	if len(f.Organizations) == 0 {
+		return true
+	}
+	for _, selected := range f.Organizations {
+		if strings.EqualFold(selected, org) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transitions returns the recorded transitions matching the filter, oldest first
+func (s *Store) Transitions(filter Filter) []Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Transition
+	for _, transition := range s.transitions {
+		if filter.matches(transition) {
+			matched = append(matched, transition)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].ObservedAt.Before(matched[j].ObservedAt)
+	})
+
+	return matched
+}
+
+// Close closes the underlying history file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
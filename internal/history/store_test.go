@@ -0,0 +1,174 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// openStore opens a store that is closed when the test ends
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// record records the migrations and returns the recorded transitions as "id:from->to"
+func record(t *testing.T, store *Store, observedAt time.Time, migrations ...models.Migration) string {
+	t.Helper()
+
+	transitions, err := store.Record("org", migrations, observedAt)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	return describeTransitions(transitions)
+}
+
+// describeTransitions describes transitions as "id:from->to"
+func describeTransitions(transitions []Transition) string {
+	var described []string
+	for _, transition := range transitions {
+		described = append(described, transition.MigrationID+":"+string(transition.FromState)+"->"+string(transition.ToState))
+	}
+	return strings.Join(described, " ")
+}
+
+func TestStoreRecordsTransitionsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", DefaultFileName)
+	store := openStore(t, path)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	queued := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateQueued}
+	running := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	legacy := []models.Migration{
+		{ID: "1", RepositoryName: "https://ghes/org/api", State: "exporting"},
+		{ID: "1", RepositoryName: "https://ghes/org/web", State: "exporting"},
+	}
+
+	steps := []struct {
+		migrations []models.Migration
+		want       string
+	}{
+		{[]models.Migration{queued}, "RM_1:->QUEUED"},
+		{[]models.Migration{queued}, ""},
+		{[]models.Migration{running}, "RM_1:QUEUED->IN_PROGRESS"},
+		{legacy, "1:->exporting 1:->exporting"},
+		{legacy, ""},
+	}
+	for i, step := range steps {
+		if got := record(t, store, start.Add(time.Duration(i)*time.Minute), step.migrations...); got != step.want {
+			t.Errorf("step %d recorded %q, want %q", i, got, step.want)
+		}
+	}
+}
+
+func TestStoreReloadsTransitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	running := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	succeeded := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateSucceeded}
+
+	store := openStore(t, path)
+	record(t, store, start, running)
+	record(t, store, start.Add(time.Hour), succeeded)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// A partially written line, e.g. after a crash, is skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{\"organization\":\"org\",\n")
+	file.Close()
+
+	reopened := openStore(t, path)
+	transitions := reopened.Transitions(Filter{})
+	if got := describeTransitions(transitions); got != "RM_1:->IN_PROGRESS RM_1:IN_PROGRESS->SUCCEEDED" {
+		t.Fatalf("reloaded transitions = %q", got)
+	}
+	if !transitions[1].ObservedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("reloaded ObservedAt = %s, want %s", transitions[1].ObservedAt, start.Add(time.Hour))
+	}
+
+	// The reloaded state is not recorded again
+	if got := record(t, reopened, start.Add(2*time.Hour), succeeded); got != "" {
+		t.Errorf("recorded %q after reloading, want nothing", got)
+	}
+	if finishedAt, ok := reopened.FinishedAt("org", succeeded); !ok || !finishedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("FinishedAt = %s, %t, want %s", finishedAt, ok, start.Add(time.Hour))
+	}
+}
+
+func TestStoreSharedBetweenProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	running := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	failed := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateFailed}
+
+	// Two stores on one file, like the dashboard and serve
+	dashboard := openStore(t, path)
+	serve := openStore(t, path)
+
+	if got := record(t, dashboard, start, running); got != "RM_1:->IN_PROGRESS" {
+		t.Errorf("dashboard recorded %q", got)
+	}
+	if got := record(t, serve, start.Add(time.Second), running); got != "" {
+		t.Errorf("serve recorded %q, want nothing as the dashboard recorded it", got)
+	}
+	if got := record(t, serve, start.Add(time.Minute), failed); got != "RM_1:IN_PROGRESS->FAILED" {
+		t.Errorf("serve recorded %q", got)
+	}
+	if got := record(t, dashboard, start.Add(time.Minute+time.Second), failed); got != "" {
+		t.Errorf("dashboard recorded %q, want nothing as serve recorded it", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("history file has %d lines, want 2:\n%s", lines, data)
+	}
+	if _, ok := dashboard.FinishedAt("org", failed); !ok {
+		t.Errorf("dashboard does not know when the migration serve saw failing finished")
+	}
+}
+
+func TestStoreFinishedAt(t *testing.T) {
+	store := openStore(t, filepath.Join(t.TempDir(), DefaultFileName))
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	observed := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	finished := models.Migration{ID: "RM_2", RepositoryName: "web", State: models.StateSucceeded}
+	record(t, store, start, observed, finished)
+
+	observed.State = models.StateFailed
+	record(t, store, start.Add(time.Hour), observed)
+	observed.State = models.StateSucceeded
+	record(t, store, start.Add(2*time.Hour), observed)
+
+	// The first time it was seen finished counts
+	if finishedAt, ok := store.FinishedAt("org", observed); !ok || !finishedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("FinishedAt(RM_1) = %s, %t, want %s", finishedAt, ok, start.Add(time.Hour))
+	}
+	// Already finished when first observed, so its end is unknown
+	if _, ok := store.FinishedAt("org", finished); ok {
+		t.Errorf("FinishedAt(RM_2) is known for a migration first seen finished")
+	}
+	if _, ok := store.FinishedAt("other", observed); ok {
+		t.Errorf("FinishedAt is known in another organization")
+	}
+}
@@ -0,0 +1,117 @@
+package logs
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// DefaultDirName is the name of the log cache directory inside the configuration directory
+const DefaultDirName = "logs"
+
+// ErrNoLog is returned for migrations that do not have a log yet
+var ErrNoLog = errors.New("migration has no log yet")
+
+// Downloader downloads the contents of a migration log
+type Downloader interface {
+	DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error
+}
+
+// Cache downloads migration logs and keeps those of finished migrations on disk
+type Cache struct {
+	dir        string
+	downloader Downloader
+}
+
+// DefaultDir returns the default log cache location, ~/.gh-migration-monitor/logs
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".gh-migration-monitor", DefaultDirName), nil
+}
+
+// NewCache creates a log cache in dir that downloads missing logs with downloader
+func NewCache(dir string, downloader Downloader) *Cache {
+	return &Cache{dir: dir, downloader: downloader}
+}
+
+// Path returns the file the migration's log is cached in
+func (c *Cache) Path(migration models.Migration) string {
+	return filepath.Join(c.dir, fileName(migration.ID))
+}
+
+// Load returns the migration's log. A cached copy is used unless refresh is set,
+// and the downloaded log is only cached once the migration has finished.
+func (c *Cache) Load(ctx context.Context, migration models.Migration, refresh bool) (string, error) {
+	path := c.Path(migration)
+
+	if !refresh {
+		if data, err := os.ReadFile(path); err == nil {
+			return string(data), nil
+		}
+	}
+
+	if migration.MigrationLogURL == "" {
+		return "", fmt.Errorf("%s: %w", migration.RepositoryName, ErrNoLog)
+	}
+
+	var b strings.Builder
+	if err := c.downloader.DownloadMigrationLog(ctx, migration.MigrationLogURL, &b); err != nil {
+		return "", err
+	}
+
+	if migration.State.IsSucceeded() || migration.State.IsFailed() {
+		// A log that cannot be cached can still be shown
+		_ = store(path, b.String())
+	}
+
+	return b.String(), nil
+}
+
+// store writes a log to path, replacing any previous copy atomically
+func store(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".log-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache log: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to cache log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache log: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// fileName returns a file name for a migration ID that is safe on every platform.
+// Migration IDs are case-sensitive, so a hash of the ID keeps the names distinct
+// on case-insensitive file systems.
+func fileName(id string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+	sum := sha256.Sum256([]byte(id))
+	return fmt.Sprintf("%s-%x.log", safe, sum[:4])
+}
@@ -0,0 +1,10 @@
+// Package logs downloads GEI migration logs and caches them locally.
+//
+// Logs are stored under ~/.gh-migration-monitor/logs/ by default, one file per
+// migration. A finished migration's log no longer changes, so it is downloaded
+// once and read from disk afterwards, while the log of a migration that is still
+// running is downloaded again every time it is requested.
+//
+// Export downloads the logs of many migrations at once into a directory laid out
+// by organization and repository, along with an index.json describing the export.
+package logs
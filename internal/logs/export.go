@@ -0,0 +1,240 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// IndexFileName is the name of the index written at the root of an export
+const IndexFileName = "index.json"
+
+// Export outcomes recorded for every migration in the index
+const (
+	ExportDownloaded = "downloaded"
+	ExportSkipped    = "skipped"
+	ExportNoLog      = "no-log"
+	ExportFailed     = "failed"
+)
+
+// ExportOptions configures a log export
+type ExportOptions struct {
+	// Concurrency is the number of logs downloaded at once
+	Concurrency int
+	// Force downloads logs again even if they were exported before
+	Force bool
+	// Progress, if set, is called after every migration with its entry
+	Progress func(entry IndexEntry)
+}
+
+// IndexEntry describes the exported log of a single migration
+type IndexEntry struct {
+	MigrationID    string       `json:"migration_id"`
+	Organization   string       `json:"organization"`
+	RepositoryName string       `json:"repository_name"`
+	State          models.State `json:"state"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Path           string       `json:"path,omitempty"`
+	Bytes          int64        `json:"bytes,omitempty"`
+	Status         string       `json:"status"`
+	Error          string       `json:"error,omitempty"`
+}
+
+// Index lists every migration of an export and where its log was written
+type Index struct {
+	ExportedAt time.Time    `json:"exported_at"`
+	Entries    []IndexEntry `json:"entries"`
+}
+
+// Count returns the number of entries with the given status
+func (idx *Index) Count(status string) int {
+	count := 0
+	for _, entry := range idx.Entries {
+		if entry.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Export downloads the logs of the migrations into dir/<org>/<repository> and
+// writes the index to dir/index.json, keeping the entries of other migrations.
+// Logs of finished migrations that were already downloaded are skipped. The
+// returned index only lists the given migrations.
+func Export(ctx context.Context, downloader Downloader, dir string, migrations []models.Migration, opts ExportOptions) (*Index, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	previous := readIndex(filepath.Join(dir, IndexFileName))
+
+	entries := make([]IndexEntry, len(migrations))
+	done := make([]bool, len(migrations))
+	var progressMu sync.Mutex
+
+	api.ForEach(ctx, len(migrations), opts.Concurrency, func(ctx context.Context, i int) {
+		entries[i] = exportLog(ctx, downloader, dir, migrations[i], previous[migrations[i].ID], opts.Force)
+		done[i] = true
+
+		if opts.Progress != nil {
+			progressMu.Lock()
+			opts.Progress(entries[i])
+			progressMu.Unlock()
+		}
+	})
+
+	// Record the migrations skipped by a cancellation as failed
+	for i, migration := range migrations {
+		if !done[i] {
+			entries[i] = newIndexEntry(migration)
+			entries[i].Status = ExportFailed
+			entries[i].Error = ctx.Err().Error()
+		}
+	}
+
+	sortEntries(entries)
+	index := &Index{ExportedAt: time.Now().UTC(), Entries: entries}
+
+	// Keep the earlier exports of migrations that were not part of this one
+	merged := append([]IndexEntry(nil), entries...)
+	for _, migration := range migrations {
+		delete(previous, migration.ID)
+	}
+	for _, entry := range previous {
+		merged = append(merged, entry)
+	}
+	sortEntries(merged)
+
+	if err := writeIndex(filepath.Join(dir, IndexFileName), &Index{ExportedAt: index.ExportedAt, Entries: merged}); err != nil {
+		return index, err
+	}
+
+	return index, nil
+}
+
+// sortEntries orders index entries by organization, repository and creation time
+func sortEntries(entries []IndexEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Organization != entries[j].Organization {
+			return entries[i].Organization < entries[j].Organization
+		}
+		if entries[i].RepositoryName != entries[j].RepositoryName {
+			return entries[i].RepositoryName < entries[j].RepositoryName
+		}
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+}
+
+// exportLog downloads a single migration's log into the export directory, unless
+// the previous export already holds its final log
+func exportLog(ctx context.Context, downloader Downloader, dir string, migration models.Migration, previous IndexEntry, force bool) IndexEntry {
+	entry := newIndexEntry(migration)
+	if migration.MigrationLogURL == "" {
+		entry.Status = ExportNoLog
+		return entry
+	}
+
+	entry.Path = filepath.ToSlash(filepath.Join(pathSegment(migration.Organization), pathSegment(migration.RepositoryName), fileName(migration.ID)))
+	path := filepath.Join(dir, filepath.FromSlash(entry.Path))
+
+	if !force && isFinalLog(previous) && previous.Path == entry.Path {
+		if info, err := os.Stat(path); err == nil {
+			entry.Status = ExportSkipped
+			entry.Bytes = info.Size()
+			return entry
+		}
+	}
+
+	var b strings.Builder
+	err := downloader.DownloadMigrationLog(ctx, migration.MigrationLogURL, &b)
+	if err == nil {
+		err = store(path, b.String())
+	}
+	if err != nil {
+		entry.Status = ExportFailed
+		entry.Error = err.Error()
+		entry.Path = ""
+		return entry
+	}
+
+	entry.Status = ExportDownloaded
+	entry.Bytes = int64(b.Len())
+	return entry
+}
+
+// newIndexEntry creates an index entry for a migration without an outcome
+func newIndexEntry(migration models.Migration) IndexEntry {
+	return IndexEntry{
+		MigrationID:    migration.ID,
+		Organization:   migration.Organization,
+		RepositoryName: migration.RepositoryName,
+		State:          migration.State,
+		CreatedAt:      migration.CreatedAt,
+	}
+}
+
+// isFinalLog returns true if an index entry holds the log of a finished migration
+func isFinalLog(entry IndexEntry) bool {
+	if entry.Status != ExportDownloaded && entry.Status != ExportSkipped {
+		return false
+	}
+	return entry.State.IsSucceeded() || entry.State.IsFailed()
+}
+
+// readIndex returns the entries of a previous export by migration ID. A missing
+// or unreadable index yields none, so every log is downloaded again.
+func readIndex(path string) map[string]IndexEntry {
+	entries := make(map[string]IndexEntry)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entries
+	}
+
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return entries
+	}
+
+	for _, entry := range index.Entries {
+		entries[entry.MigrationID] = entry
+	}
+	return entries
+}
+
+// writeIndex writes the export index as indented JSON
+func writeIndex(path string, index *Index) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export index: %w", err)
+	}
+	if err := store(path, string(data)+"\n"); err != nil {
+		return fmt.Errorf("failed to write export index: %w", err)
+	}
+	return nil
+}
+
+// pathSegment returns an organization or repository name that is safe to use as
+// a directory name
+func pathSegment(name string) string {
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		default:
+			return r
+		}
+	}, name)
+}
@@ -0,0 +1,8 @@
+// Package manifest loads migration wave manifests and reconciles them with the
+// migrations reported by GitHub.
+//
+// A manifest is a CSV file listing the repositories expected to be migrated in a
+// wave. Reconciling it against the actual migrations shows which expected
+// repositories have a migration and in which state, which have not been started
+// yet, and which migrations were not expected by the manifest at all.
+package manifest
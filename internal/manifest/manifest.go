@@ -0,0 +1,135 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Recognized header names for each manifest column, compared case-insensitively
+var (
+	sourceColumns       = []string{"source", "source_repo", "source_repository", "source_url", "github_src_repo"}
+	organizationColumns = []string{"target_org", "target_organization", "organization", "github_target_org"}
+	repositoryColumns   = []string{"target", "target_repo", "target_repository", "target_name", "repository", "github_target_repo"}
+)
+
+// Entry is a repository the manifest expects to be migrated
+type Entry struct {
+	// Line is the line number of the entry in the manifest file
+	Line         int    `json:"line" yaml:"line"`
+	Source       string `json:"source,omitempty" yaml:"source,omitempty"`
+	Organization string `json:"organization,omitempty" yaml:"organization,omitempty"`
+	Repository   string `json:"repository" yaml:"repository"`
+}
+
+// Manifest is the list of repositories expected to be migrated in a wave
+type Manifest struct {
+	Path    string
+	Entries []Entry
+}
+
+// Load reads a manifest from a CSV file
+func Load(path string) (*Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	entries, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	return &Manifest{Path: path, Entries: entries}, nil
+}
+
+// Parse reads manifest entries from CSV with a header row.
+//
+// The target repository is read from a column such as target_repo, and the
+// optional target organization and source from target_org and source_repo. When
+// there is no target column the repository name is taken from the source, so a
+// manifest of source URLs or "org/repo" names also works.
+func Parse(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("manifest is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source := findColumn(header, sourceColumns)
+	organization := findColumn(header, organizationColumns)
+	repository := findColumn(header, repositoryColumns)
+	if repository < 0 && source < 0 {
+		return nil, fmt.Errorf("manifest needs a target repository column (%s) or a source column (%s)",
+			strings.Join(repositoryColumns, ", "), strings.Join(sourceColumns, ", "))
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		entry := Entry{
+			Line:         line,
+			Source:       field(record, source),
+			Organization: field(record, organization),
+			Repository:   field(record, repository),
+		}
+		if entry.Repository == "" {
+			entry.Repository = repositoryName(entry.Source)
+		}
+
+		// Skip blank rows
+		if entry.Repository == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// findColumn returns the index of the first header matching one of the names, or -1
+func findColumn(header []string, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// field returns the trimmed value of a column, or an empty string if it is missing
+func field(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// repositoryName extracts the repository name from a source URL or "org/repo" name
+func repositoryName(source string) string {
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+	if i := strings.LastIndex(source, "/"); i >= 0 {
+		return source[i+1:]
+	}
+	return source
+}
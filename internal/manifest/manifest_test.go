@@ -0,0 +1,83 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []Entry
+		wantErr string
+	}{
+		{
+			name: "target columns",
+			csv:  "target_org,target_repo\nacme,api\nAcme, web \n",
+			want: []Entry{
+				{Line: 2, Organization: "acme", Repository: "api"},
+				{Line: 3, Organization: "Acme", Repository: "web"},
+			},
+		},
+		{
+			name: "header names are case-insensitive",
+			csv:  "GitHub_Src_Repo,GITHUB_TARGET_ORG,Github_Target_Repo\nhttps://ghes.example.com/team/api,acme,api-new\n",
+			want: []Entry{
+				{Line: 2, Source: "https://ghes.example.com/team/api", Organization: "acme", Repository: "api-new"},
+			},
+		},
+		{
+			name: "target name derived from a source URL",
+			csv:  "source_url\nhttps://ghes.example.com/team/api.git\nhttps://ghes.example.com/team/web/\n",
+			want: []Entry{
+				{Line: 2, Source: "https://ghes.example.com/team/api.git", Repository: "api"},
+				{Line: 3, Source: "https://ghes.example.com/team/web/", Repository: "web"},
+			},
+		},
+		{
+			name: "target name derived from an org/repo source",
+			csv:  "source,target_repo\nteam/api,\nteam/web,web-new\n",
+			want: []Entry{
+				{Line: 2, Source: "team/api", Repository: "api"},
+				{Line: 3, Source: "team/web", Repository: "web-new"},
+			},
+		},
+		{
+			name: "blank rows and short records",
+			csv:  "target_org,target_repo\n,\nacme\nacme,api\n",
+			want: []Entry{
+				{Line: 4, Organization: "acme", Repository: "api"},
+			},
+		},
+		{
+			name:    "empty manifest",
+			csv:     "",
+			wantErr: "manifest is empty",
+		},
+		{
+			name:    "no repository column",
+			csv:     "target_org,owner\nacme,octocat\n",
+			wantErr: "needs a target repository column",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Parse(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", entries, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// Row statuses that are not migration state buckets
+const (
+	StatusNotStarted = "not_migrated"
+	StatusUnexpected = "unexpected"
+)
+
+// Row pairs a manifest entry with its migration. Rows for repositories without a
+// migration have no Migration, and rows for unexpected migrations have no Entry.
+type Row struct {
+	Entry     *Entry            `json:"entry,omitempty" yaml:"entry,omitempty"`
+	Migration *models.Migration `json:"migration,omitempty" yaml:"migration,omitempty"`
+}
+
+// Status returns not_migrated, unexpected or the lowercase state of the migration
+func (r Row) Status() string {
+	switch {
+	case r.Migration == nil:
+		return StatusNotStarted
+	case r.Entry == nil:
+		return StatusUnexpected
+	default:
+		return strings.ToLower(string(r.Migration.State))
+	}
+}
+
+// Effective returns the row's migration, or a NOT_MIGRATED placeholder for an
+// entry without one, so rows can be filtered and displayed like migrations
+func (r Row) Effective() models.Migration {
+	if r.Migration != nil {
+		return *r.Migration
+	}
+	return r.Entry.Placeholder()
+}
+
+// Placeholder returns a NOT_MIGRATED migration standing in for the entry
+func (e Entry) Placeholder() models.Migration {
+	placeholder := models.Migration{
+		Organization:   e.Organization,
+		RepositoryName: e.Repository,
+		State:          models.StateNotMigrated,
+	}
+	if strings.HasPrefix(e.Source, "http://") || strings.HasPrefix(e.Source, "https://") {
+		placeholder.SourceURL = e.Source
+	}
+	return placeholder
+}
+
+// Reconciliation is the result of comparing a manifest with the actual migrations
+type Reconciliation struct {
+	Rows       []Row `json:"rows" yaml:"rows"`
+	Expected   int   `json:"expected" yaml:"expected"`
+	NotStarted int   `json:"not_started" yaml:"not_started"`
+	Unexpected int   `json:"unexpected" yaml:"unexpected"`
+
+	// migrations holds every migration, including earlier attempts of expected repositories
+	migrations []models.Migration
+	unexpected map[string]bool
+}
+
+// Reconcile matches every manifest entry with the latest migration of its
+// repository. Entries without a target organization match the repository in any
+// organization. Migrations of repositories that are not in the manifest are
+// reported as unexpected.
+func (m *Manifest) Reconcile(migrations []models.Migration) *Reconciliation {
+	rec := &Reconciliation{
+		Expected:   len(m.Entries),
+		migrations: migrations,
+		unexpected: make(map[string]bool),
+	}
+
+	// Find the latest migration of every repository, with and without its organization
+	latest := make(map[string]int)
+	for i, migration := range migrations {
+		for _, key := range []string{repositoryKey("", migration.RepositoryName), repositoryKey(migration.Organization, migration.RepositoryName)} {
+			if j, ok := latest[key]; !ok || migration.CreatedAt.After(migrations[j].CreatedAt) {
+				latest[key] = i
+			}
+		}
+	}
+
+	expected := make(map[string]bool)
+	for i := range m.Entries {
+		entry := &m.Entries[i]
+		key := repositoryKey(entry.Organization, entry.Repository)
+		expected[key] = true
+
+		row := Row{Entry: entry}
+		if j, ok := latest[key]; ok {
+			row.Migration = &migrations[j]
+		} else {
+			rec.NotStarted++
+		}
+		rec.Rows = append(rec.Rows, row)
+	}
+
+	// Report each unexpected repository once, by its latest migration
+	reported := make(map[string]bool)
+	for _, migration := range migrations {
+		if expected[repositoryKey("", migration.RepositoryName)] || expected[repositoryKey(migration.Organization, migration.RepositoryName)] {
+			continue
+		}
+
+		rec.unexpected[migrationKey(migration)] = true
+		key := repositoryKey(migration.Organization, migration.RepositoryName)
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		rec.Unexpected++
+		rec.Rows = append(rec.Rows, Row{Migration: &migrations[latest[key]]})
+	}
+
+	return rec
+}
+
+// Migrations returns every migration together with a NOT_MIGRATED placeholder for
+// each entry that has no migration yet
+func (r *Reconciliation) Migrations() []models.Migration {
+	all := make([]models.Migration, 0, len(r.migrations)+r.NotStarted)
+	for _, row := range r.Rows {
+		if row.Migration == nil {
+			all = append(all, row.Effective())
+		}
+	}
+	return append(all, r.migrations...)
+}
+
+// IsUnexpected returns true if the migration's repository is not in the manifest
+func (r *Reconciliation) IsUnexpected(migration models.Migration) bool {
+	return r.unexpected[migrationKey(migration)]
+}
+
+// repositoryKey identifies a repository, optionally within an organization
+func repositoryKey(organization, repository string) string {
+	return strings.ToLower(organization) + "/" + strings.ToLower(repository)
+}
+
+// migrationKey identifies a single migration
+func migrationKey(migration models.Migration) string {
+	return migration.ID + "\x00" + repositoryKey(migration.Organization, migration.RepositoryName)
+}
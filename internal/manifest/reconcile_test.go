@@ -0,0 +1,155 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// migration creates a migration created the given number of hours after a fixed time
+func migration(id, organization, repository string, state models.State, hours int) models.Migration {
+	return models.Migration{
+		ID:             id,
+		Organization:   organization,
+		RepositoryName: repository,
+		State:          state,
+		CreatedAt:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).Add(time.Duration(hours) * time.Hour),
+	}
+}
+
+// describeRows describes rows as "repository=status/migration ID"
+func describeRows(rows []Row) string {
+	var described []string
+	for _, row := range rows {
+		repository := ""
+		if row.Entry != nil {
+			repository = row.Entry.Repository
+		} else {
+			repository = row.Migration.Organization + "/" + row.Migration.RepositoryName
+		}
+		id := ""
+		if row.Migration != nil {
+			id = row.Migration.ID
+		}
+		described = append(described, repository+"="+row.Status()+"/"+id)
+	}
+	return strings.Join(described, " ")
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		entries        []Entry
+		migrations     []models.Migration
+		want           string
+		wantNotStarted int
+		wantUnexpected int
+	}{
+		{
+			name:    "entry without an organization matches any organization",
+			entries: []Entry{{Repository: "api"}},
+			migrations: []models.Migration{
+				migration("RM_1", "acme", "API", models.StateSucceeded, 0),
+			},
+			want: "api=succeeded/RM_1",
+		},
+		{
+			name:    "entry with an organization only matches that organization",
+			entries: []Entry{{Organization: "Acme", Repository: "api"}},
+			migrations: []models.Migration{
+				migration("RM_1", "other", "api", models.StateSucceeded, 0),
+			},
+			want:           "api=not_migrated/ other/api=unexpected/RM_1",
+			wantNotStarted: 1,
+			wantUnexpected: 1,
+		},
+		{
+			name:    "latest migration wins",
+			entries: []Entry{{Organization: "acme", Repository: "api"}},
+			migrations: []models.Migration{
+				migration("RM_2", "acme", "api", models.StateInProgress, 2),
+				migration("RM_1", "acme", "api", models.StateFailed, 1),
+				migration("RM_3", "acme", "api", models.StateQueued, 0),
+			},
+			want: "api=in_progress/RM_2",
+		},
+		{
+			name:    "unexpected migrations are reported once per repository",
+			entries: []Entry{{Repository: "api"}},
+			migrations: []models.Migration{
+				migration("RM_1", "acme", "api", models.StateSucceeded, 0),
+				migration("RM_2", "acme", "web", models.StateFailed, 0),
+				migration("RM_3", "acme", "web", models.StateSucceeded, 1),
+				migration("RM_4", "other", "web", models.StateQueued, 0),
+			},
+			want:           "api=succeeded/RM_1 acme/web=unexpected/RM_3 other/web=unexpected/RM_4",
+			wantUnexpected: 2,
+		},
+		{
+			name:           "entries without a migration are not migrated",
+			entries:        []Entry{{Repository: "api"}, {Organization: "acme", Repository: "web"}},
+			want:           "api=not_migrated/ web=not_migrated/",
+			wantNotStarted: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{Entries: tt.entries}
+			rec := m.Reconcile(tt.migrations)
+
+			if got := describeRows(rec.Rows); got != tt.want {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+			if rec.Expected != len(tt.entries) || rec.NotStarted != tt.wantNotStarted || rec.Unexpected != tt.wantUnexpected {
+				t.Errorf("expected %d, not started %d, unexpected %d, want %d, %d and %d",
+					rec.Expected, rec.NotStarted, rec.Unexpected, len(tt.entries), tt.wantNotStarted, tt.wantUnexpected)
+			}
+		})
+	}
+}
+
+func TestReconciliationMigrations(t *testing.T) {
+	m := &Manifest{Entries: []Entry{
+		{Source: "https://ghes.example.com/team/api", Organization: "acme", Repository: "api"},
+		{Organization: "acme", Repository: "web"},
+	}}
+	migrations := []models.Migration{
+		migration("RM_1", "acme", "web", models.StateFailed, 0),
+		migration("RM_2", "acme", "web", models.StateSucceeded, 1),
+		migration("RM_3", "acme", "docs", models.StateSucceeded, 0),
+	}
+
+	rec := m.Reconcile(migrations)
+	all := rec.Migrations()
+
+	// The placeholder comes first, followed by every migration including earlier attempts
+	if len(all) != 4 {
+		t.Fatalf("got %d migrations, want 4", len(all))
+	}
+	placeholder := all[0]
+	if placeholder.State != models.StateNotMigrated || placeholder.Organization != "acme" ||
+		placeholder.RepositoryName != "api" || placeholder.SourceURL != "https://ghes.example.com/team/api" {
+		t.Errorf("placeholder = %+v, want a NOT_MIGRATED acme/api with its source URL", placeholder)
+	}
+	for i, want := range []string{"RM_1", "RM_2", "RM_3"} {
+		if all[i+1].ID != want {
+			t.Errorf("migrations[%d] = %s, want %s", i+1, all[i+1].ID, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		migration  models.Migration
+		unexpected bool
+	}{
+		{migrations[0], false},
+		{migrations[1], false},
+		{migrations[2], true},
+	} {
+		if got := rec.IsUnexpected(tt.migration); got != tt.unexpected {
+			t.Errorf("IsUnexpected(%s) = %t, want %t", tt.migration.ID, got, tt.unexpected)
+		}
+	}
+}
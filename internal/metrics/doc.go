@@ -0,0 +1,7 @@
+// Package metrics exposes migration progress in the Prometheus text format.
+//
+// A Collector tracks the number of migrations per organization and state bucket,
+// counts the state transitions reported by the change detector and records the
+// latency and outcome of every GitHub API request, so long migration waves can be
+// graphed and alerted on in existing monitoring stacks.
+package metrics
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every exported metric name
+const namespace = "gh_migration_monitor"
+
+// State bucket label values, matching the dashboard columns
+const (
+	BucketNotStarted = "not_started"
+	BucketQueued     = "queued"
+	BucketInProgress = "in_progress"
+	BucketSucceeded  = "succeeded"
+	BucketFailed     = "failed"
+)
+
+// Collector records migration and API metrics and serves them to Prometheus
+type Collector struct {
+	registry *prometheus.Registry
+
+	migrations  *prometheus.GaugeVec
+	transitions *prometheus.CounterVec
+	refreshes   *prometheus.CounterVec
+	lastRefresh *prometheus.GaugeVec
+	apiRequests *prometheus.CounterVec
+	apiErrors   *prometheus.CounterVec
+	apiDuration *prometheus.HistogramVec
+}
+
+// NewCollector creates a collector with its own registry, including the standard
+// Go runtime and process metrics
+func NewCollector() *Collector {
+	c := &Collector{
+		registry: prometheus.NewRegistry(),
+		migrations: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "migrations",
+			Help:      "Number of repository migrations by organization and state bucket.",
+		}, []string{"organization", "state"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "migration_transitions_total",
+			Help:      "Migration state transitions observed between refreshes. New migrations have an empty from label.",
+		}, []string{"organization", "from", "to"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "refreshes_total",
+			Help:      "Migration refreshes by organization and result.",
+		}, []string{"organization", "result"}),
+		lastRefresh: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_successful_refresh_timestamp_seconds",
+			Help:      "Unix time of the last successful migration refresh.",
+		}, []string{"organization"}),
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "GitHub API requests by API and HTTP status code. Requests that received no response have code \"error\".",
+		}, []string{"api", "code"}),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_errors_total",
+			Help:      "GitHub API requests that failed or returned an error status.",
+		}, []string{"api"}),
+		apiDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "GitHub API request latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"api"}),
+	}
+
+	c.registry.MustRegister(
+		c.migrations,
+		c.transitions,
+		c.refreshes,
+		c.lastRefresh,
+		c.apiRequests,
+		c.apiErrors,
+		c.apiDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return c
+}
+
+// ObserveSummary updates the migration gauges for an organization
+func (c *Collector) ObserveSummary(org string, summary *models.MigrationSummary) {
+	c.migrations.WithLabelValues(org, BucketNotStarted).Set(float64(len(summary.NotStarted)))
+	c.migrations.WithLabelValues(org, BucketQueued).Set(float64(len(summary.Queued)))
+	c.migrations.WithLabelValues(org, BucketInProgress).Set(float64(len(summary.InProgress)))
+	c.migrations.WithLabelValues(org, BucketSucceeded).Set(float64(len(summary.Succeeded)))
+	c.migrations.WithLabelValues(org, BucketFailed).Set(float64(len(summary.Failed)))
+}
+
+// ObserveRefresh counts a refresh and, if it succeeded, records its time
+func (c *Collector) ObserveRefresh(org string, err error, at time.Time) {
+	if err != nil {
+		c.refreshes.WithLabelValues(org, "error").Inc()
+		return
+	}
+
+	c.refreshes.WithLabelValues(org, "success").Inc()
+	c.lastRefresh.WithLabelValues(org).Set(float64(at.Unix()))
+}
+
+// ObserveEvent counts state transitions. It is intended to be registered with
+// ChangeDetector.Subscribe; events that do not change the state are ignored.
+func (c *Collector) ObserveEvent(event services.Event) {
+	if !event.ChangesState() {
+		return
+	}
+
+	c.transitions.WithLabelValues(
+		event.Organization,
+		stateLabel(event.PreviousState()),
+		stateLabel(event.Migration.State),
+	).Inc()
+}
+
+// ObserveRequest records a GitHub API request. It matches api.RequestObserver.
+func (c *Collector) ObserveRequest(info api.RequestInfo) {
+	code := "error"
+	if info.Err == nil {
+		code = strconv.Itoa(info.StatusCode)
+	}
+
+	c.apiRequests.WithLabelValues(info.API, code).Inc()
+	c.apiDuration.WithLabelValues(info.API).Observe(info.Duration.Seconds())
+	if info.Failed() {
+		c.apiErrors.WithLabelValues(info.API).Inc()
+	}
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus text format
+func (c *Collector) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
+
+// stateLabel formats a migration state as a label value
+func stateLabel(state models.State) string {
+	return strings.ToLower(string(state))
+}
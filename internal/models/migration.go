@@ -1,15 +1,64 @@
 package models
 
-import "time"
+import (
+	"maps"
+	"sort"
+	"time"
+)
 
 // Migration represents a GitHub repository migration
 type Migration struct {
-	ID              string    `json:"id"`
-	RepositoryName  string    `json:"repository_name"`
-	State           State     `json:"state"`
-	CreatedAt       time.Time `json:"created_at"`
-	FailureReason   string    `json:"failure_reason,omitempty"`
-	MigrationLogURL string    `json:"migration_log_url,omitempty"`
+	ID              string    `json:"id" yaml:"id"`
+	Organization    string    `json:"organization,omitempty" yaml:"organization,omitempty"`
+	RepositoryName  string    `json:"repository_name" yaml:"repository_name"`
+	State           State     `json:"state" yaml:"state"`
+	CreatedAt       time.Time `json:"created_at" yaml:"created_at"`
+	FailureReason   string    `json:"failure_reason,omitempty" yaml:"failure_reason,omitempty"`
+	MigrationLogURL string    `json:"migration_log_url,omitempty" yaml:"migration_log_url,omitempty"`
+	DatabaseID      string    `json:"database_id,omitempty" yaml:"database_id,omitempty"`
+	SourceURL       string    `json:"source_url,omitempty" yaml:"source_url,omitempty"`
+	WarningsCount   int       `json:"warnings_count" yaml:"warnings_count"`
+	ContinueOnError bool      `json:"continue_on_error" yaml:"continue_on_error"`
+
+	MigrationSource MigrationSource `json:"migration_source" yaml:"migration_source"`
+
+	// ResourceCounts holds the number of migratable resources of each type, such as
+	// repository, project or team, in a legacy migration
+	ResourceCounts map[string]int `json:"resource_counts,omitempty" yaml:"resource_counts,omitempty"`
+}
+
+// Equal returns true if both migrations have the same field values
+func (m Migration) Equal(other Migration) bool {
+	return m.ID == other.ID &&
+		m.Organization == other.Organization &&
+		m.RepositoryName == other.RepositoryName &&
+		m.State == other.State &&
+		m.CreatedAt.Equal(other.CreatedAt) &&
+		m.FailureReason == other.FailureReason &&
+		m.MigrationLogURL == other.MigrationLogURL &&
+		m.DatabaseID == other.DatabaseID &&
+		m.SourceURL == other.SourceURL &&
+		m.WarningsCount == other.WarningsCount &&
+		m.ContinueOnError == other.ContinueOnError &&
+		m.MigrationSource == other.MigrationSource &&
+		maps.Equal(m.ResourceCounts, other.ResourceCounts)
+}
+
+// FullName returns "org/repo" for the migration's repository, or only the
+// repository name when the organization is unknown
+func (m Migration) FullName() string {
+	if m.Organization == "" {
+		return m.RepositoryName
+	}
+	return m.Organization + "/" + m.RepositoryName
+}
+
+// MigrationSource describes where a GEI migration imports from
+type MigrationSource struct {
+	ID   string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 // State represents the current state of a migration
@@ -31,11 +80,25 @@ const (
 	StateImported     State = "IMPORTED"
 	StateFailed       State = "FAILED"
 	StateFailedImport State = "FAILED_IMPORT"
+
+	// StateNotStarted is reported by GEI for migrations that were created but not
+	// started yet, which are queued
+	StateNotStarted State = "NOT_STARTED"
+
+	// StateNotMigrated marks a repository expected by a migration manifest that has no
+	// migration yet. GEI never reports it.
+	StateNotMigrated State = "NOT_MIGRATED"
 )
 
+// IsNotMigrated returns true for a repository of a migration manifest that has no
+// migration yet
+func (s State) IsNotMigrated() bool {
+	return s == StateNotMigrated
+}
+
 // IsQueued returns true if the migration is in a queued state
 func (s State) IsQueued() bool {
-	return s == StateQueued || s == StateWaiting
+	return s == StateQueued || s == StateWaiting || s == StateNotStarted
 }
 
 // IsInProgress returns true if the migration is in progress
@@ -57,21 +120,84 @@ func (s State) IsFailed() bool {
 
 // MigrationSummary provides a summary of migrations by state
 type MigrationSummary struct {
-	Queued     []Migration `json:"queued"`
-	InProgress []Migration `json:"in_progress"`
-	Succeeded  []Migration `json:"succeeded"`
-	Failed     []Migration `json:"failed"`
+	NotStarted []Migration `json:"not_started,omitempty" yaml:"not_started,omitempty"`
+	Queued     []Migration `json:"queued" yaml:"queued"`
+	InProgress []Migration `json:"in_progress" yaml:"in_progress"`
+	Succeeded  []Migration `json:"succeeded" yaml:"succeeded"`
+	Failed     []Migration `json:"failed" yaml:"failed"`
+}
+
+// NewMigrationSummary categorizes migrations into state buckets
+func NewMigrationSummary(migrations []Migration) *MigrationSummary {
+	summary := &MigrationSummary{
+		Queued:     make([]Migration, 0),
+		InProgress: make([]Migration, 0),
+		Succeeded:  make([]Migration, 0),
+		Failed:     make([]Migration, 0),
+	}
+
+	for _, migration := range migrations {
+		switch {
+		case migration.State.IsNotMigrated():
+			summary.NotStarted = append(summary.NotStarted, migration)
+		case migration.State.IsQueued():
+			summary.Queued = append(summary.Queued, migration)
+		case migration.State.IsInProgress():
+			summary.InProgress = append(summary.InProgress, migration)
+		case migration.State.IsSucceeded():
+			summary.Succeeded = append(summary.Succeeded, migration)
+		case migration.State.IsFailed():
+			summary.Failed = append(summary.Failed, migration)
+		}
+	}
+
+	return summary
 }
 
 // Total returns the total number of migrations
 func (ms *MigrationSummary) Total() int {
-	return len(ms.Queued) + len(ms.InProgress) + len(ms.Succeeded) + len(ms.Failed)
+	return len(ms.NotStarted) + len(ms.Queued) + len(ms.InProgress) + len(ms.Succeeded) + len(ms.Failed)
+}
+
+// All returns every migration in the summary ordered not started, queued, in progress, succeeded, failed
+func (ms *MigrationSummary) All() []Migration {
+	all := make([]Migration, 0, ms.Total())
+	all = append(all, ms.NotStarted...)
+	all = append(all, ms.Queued...)
+	all = append(all, ms.InProgress...)
+	all = append(all, ms.Succeeded...)
+	all = append(all, ms.Failed...)
+	return all
+}
+
+// Organizations returns the distinct organizations of the migrations in the summary, sorted by name
+func (ms *MigrationSummary) Organizations() []string {
+	seen := make(map[string]bool)
+	var organizations []string
+	for _, migration := range ms.All() {
+		if migration.Organization != "" && !seen[migration.Organization] {
+			seen[migration.Organization] = true
+			organizations = append(organizations, migration.Organization)
+		}
+	}
+	sort.Strings(organizations)
+	return organizations
 }
 
 // ListOptions represents options for listing migrations
 type ListOptions struct {
 	Organization string `json:"organization"`
-	State        State  `json:"state,omitempty"`
-	Limit        int    `json:"limit,omitempty"`
-	Page         int    `json:"page,omitempty"`
+	// State only lists migrations in this exact state; empty lists every state
+	State State `json:"state,omitempty"`
+	// RepositoryName only lists migrations of this repository
+	RepositoryName string `json:"repository_name,omitempty"`
+	// Since and Until only list migrations created at or after Since and before
+	// Until; a zero time leaves that end open
+	Since time.Time `json:"since,omitempty"`
+	Until time.Time `json:"until,omitempty"`
+	// Limit caps the number of migrations listed; zero lists every migration
+	Limit int `json:"limit,omitempty"`
+	// Page selects a single page of the paged legacy REST listing; zero lists
+	// every page. GEI migrations are paged with cursors and ignore it.
+	Page int `json:"page,omitempty"`
 }
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// CommandNotifier runs a user-configured shell command for every event.
+//
+// The migration is written to the command's standard input as JSON, and the event
+// details are exposed through GHMM_* environment variables.
+type CommandNotifier struct {
+	Command string
+}
+
+// NewCommandNotifier creates a notifier that runs command through the system shell
+func NewCommandNotifier(command string) *CommandNotifier {
+	return &CommandNotifier{Command: command}
+}
+
+// Notify implements Notifier.Notify
+func (n *CommandNotifier) Notify(ctx context.Context, event services.Event) error {
+	payload, err := json.Marshal(event.Migration)
+	if err != nil {
+		return fmt.Errorf("failed to encode migration: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", n.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", n.Command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"GHMM_EVENT_TYPE="+string(event.Type),
+		"GHMM_ORGANIZATION="+event.Organization,
+		"GHMM_MIGRATION_ID="+event.Migration.ID,
+		"GHMM_REPOSITORY_NAME="+event.Migration.RepositoryName,
+		"GHMM_STATE="+string(event.Migration.State),
+		"GHMM_PREVIOUS_STATE="+string(event.PreviousState()),
+	)
+
+	if err := cmd.Run(); err != nil {
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			return fmt.Errorf("notification command failed: %w: %s", err, output)
+		}
+		return fmt.Errorf("notification command failed: %w", err)
+	}
+
+	return nil
+}
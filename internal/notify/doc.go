@@ -0,0 +1,8 @@
+// Package notify delivers notifications about migration events.
+//
+// Notifiers subscribe to the change detection events produced by the services
+// package and alert users when migrations finish or fail, using a terminal bell,
+// an OSC 9/777 desktop notification escape sequence or a user-configured shell
+// command. Webhooks receive every state change, optionally filtered by outcome,
+// as JSON or as a Slack or Microsoft Teams message.
+package notify
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// Notifier delivers a notification for a migration event
+type Notifier interface {
+	Notify(ctx context.Context, event services.Event) error
+}
+
+// notifyTimeout bounds how long a single notifier may take for one event
+const notifyTimeout = time.Minute
+
+// EventFilter selects which migration events a notifier receives
+type EventFilter string
+
+const (
+	// FilterAll matches every state change, including newly appeared migrations
+	FilterAll EventFilter = "all"
+	// FilterCompleted matches migrations that succeeded or failed
+	FilterCompleted EventFilter = "completed"
+	// FilterSucceeded matches migrations that succeeded
+	FilterSucceeded EventFilter = "succeeded"
+	// FilterFailed matches migrations that failed
+	FilterFailed EventFilter = "failed"
+)
+
+// ParseEventFilters validates event filter names. No names selects FilterAll.
+func ParseEventFilters(names []string) ([]EventFilter, error) {
+	if len(names) == 0 {
+		return []EventFilter{FilterAll}, nil
+	}
+
+	filters := make([]EventFilter, 0, len(names))
+	for _, name := range names {
+		filter := EventFilter(strings.ToLower(strings.TrimSpace(name)))
+		switch filter {
+		case FilterAll, FilterCompleted, FilterSucceeded, FilterFailed:
+			filters = append(filters, filter)
+		default:
+			return nil, fmt.Errorf("invalid event filter %q (valid: all, completed, succeeded, failed)", name)
+		}
+	}
+	return filters, nil
+}
+
+// Matches returns true if the filter selects the event. Only events where a
+// migration enters its state are ever matched.
+func (f EventFilter) Matches(event services.Event) bool {
+	if !event.ChangesState() {
+		return false
+	}
+
+	state := event.Migration.State
+	switch f {
+	case FilterAll:
+		return true
+	case FilterCompleted:
+		return state.IsSucceeded() || state.IsFailed()
+	case FilterSucceeded:
+		return state.IsSucceeded()
+	case FilterFailed:
+		return state.IsFailed()
+	default:
+		return false
+	}
+}
+
+// route pairs a notifier with the events it should receive
+type route struct {
+	notifier Notifier
+	filters  []EventFilter
+}
+
+// matches checks if any of the route's filters select the event
+func (r route) matches(event services.Event) bool {
+	for _, filter := range r.filters {
+		if filter.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher forwards migration events to a set of notifiers.
+//
+// Notifications are delivered in the background so that a slow notifier never
+// delays a dashboard refresh. Delivery errors are reported to the error handler.
+type Dispatcher struct {
+	routes  []route
+	onError func(error)
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher creates an empty dispatcher. The error handler may be nil, in
+// which case delivery errors are discarded.
+func NewDispatcher(onError func(error)) *Dispatcher {
+	return &Dispatcher{
+		onError: onError,
+	}
+}
+
+// Add registers a notifier for the events matching any of the filters
+func (d *Dispatcher) Add(notifier Notifier, filters ...EventFilter) {
+	d.routes = append(d.routes, route{notifier: notifier, filters: filters})
+}
+
+// Empty returns true if the dispatcher has no notifiers configured
+func (d *Dispatcher) Empty() bool {
+	return len(d.routes) == 0
+}
+
+// Handle delivers an event to every notifier whose filters match it. It is
+// intended to be registered with ChangeDetector.Subscribe. Events handled after
+// Wait was called are dropped.
+func (d *Dispatcher) Handle(event services.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+
+	for _, r := range d.routes {
+		if !r.matches(event) {
+			continue
+		}
+
+		d.wg.Add(1)
+		go func(notifier Notifier) {
+			defer d.wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+
+			if err := notifier.Notify(ctx, event); err != nil && d.onError != nil {
+				d.onError(err)
+			}
+		}(r.notifier)
+	}
+}
+
+// Wait stops accepting events and blocks until the notifications in flight have
+// been delivered or the timeout passed. It returns false if some were still
+// being delivered.
+func (d *Dispatcher) Wait(timeout time.Duration) bool {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// summarize returns a short title and message describing an event
+func summarize(event services.Event) (string, string) {
+	migration := event.Migration
+	title := fmt.Sprintf("Migration %s", migration.State)
+
+	switch {
+	case migration.State.IsFailed():
+		title = "Migration failed"
+	case migration.State.IsSucceeded():
+		title = "Migration succeeded"
+	}
+
+	message := fmt.Sprintf("%s/%s is %s", event.Organization, migration.RepositoryName, migration.State)
+	if previous := event.PreviousState(); previous != "" {
+		message += fmt.Sprintf(" (was %s)", previous)
+	}
+	if migration.State.IsFailed() && migration.FailureReason != "" {
+		message += ": " + migration.FailureReason
+	}
+
+	return title, message
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// notifierFunc adapts a function to the Notifier interface
+type notifierFunc func(ctx context.Context, event services.Event) error
+
+func (f notifierFunc) Notify(ctx context.Context, event services.Event) error {
+	return f(ctx, event)
+}
+
+func TestDispatcherWait(t *testing.T) {
+	var delivered atomic.Int32
+	release := make(chan struct{})
+
+	dispatcher := NewDispatcher(nil)
+	dispatcher.Add(notifierFunc(func(ctx context.Context, event services.Event) error {
+		<-release
+		delivered.Add(1)
+		return nil
+	}), FilterFailed)
+
+	dispatcher.Handle(failedEvent())
+	if dispatcher.Wait(10 * time.Millisecond) {
+		t.Fatalf("Wait returned true while a notification was being delivered")
+	}
+
+	// Events after Wait are dropped, the one in flight is still delivered
+	dispatcher.Handle(failedEvent())
+	close(release)
+	if !dispatcher.Wait(time.Second) {
+		t.Fatalf("Wait returned false after the notification was delivered")
+	}
+	if got := delivered.Load(); got != 1 {
+		t.Errorf("delivered %d notifications, want 1", got)
+	}
+}
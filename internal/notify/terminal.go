@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// Desktop notification escape sequence protocols
+const (
+	// ProtocolOSC9 is supported by iTerm2, Windows Terminal, WezTerm and others
+	ProtocolOSC9 = "osc9"
+	// ProtocolOSC777 is supported by rxvt-unicode, foot, Ghostty and VTE based terminals
+	ProtocolOSC777 = "osc777"
+)
+
+// terminalMu serializes writes of escape sequences to the terminal
+var terminalMu sync.Mutex
+
+// BellNotifier rings the terminal bell
+type BellNotifier struct {
+	Writer io.Writer
+}
+
+// NewBellNotifier creates a notifier that rings the bell on standard output
+func NewBellNotifier() *BellNotifier {
+	return &BellNotifier{Writer: os.Stdout}
+}
+
+// Notify implements Notifier.Notify
+func (n *BellNotifier) Notify(ctx context.Context, event services.Event) error {
+	return writeTerminal(n.Writer, "\a")
+}
+
+// DesktopNotifier emits an OSC 9 or OSC 777 escape sequence that supporting
+// terminals turn into a desktop notification
+type DesktopNotifier struct {
+	Writer   io.Writer
+	Protocol string
+	// InTmux wraps the sequence in a tmux passthrough so it reaches the outer terminal
+	InTmux bool
+}
+
+// NewDesktopNotifier creates a desktop notifier writing to standard output
+func NewDesktopNotifier(protocol string) (*DesktopNotifier, error) {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol != ProtocolOSC9 && protocol != ProtocolOSC777 {
+		return nil, fmt.Errorf("invalid desktop notification protocol %q (valid: %s, %s)", protocol, ProtocolOSC9, ProtocolOSC777)
+	}
+
+	return &DesktopNotifier{
+		Writer:   os.Stdout,
+		Protocol: protocol,
+		InTmux:   os.Getenv("TMUX") != "",
+	}, nil
+}
+
+// Notify implements Notifier.Notify
+func (n *DesktopNotifier) Notify(ctx context.Context, event services.Event) error {
+	title, message := summarize(event)
+	title = sanitizeOSC(title)
+	message = sanitizeOSC(message)
+
+	var sequence string
+	switch n.Protocol {
+	case ProtocolOSC777:
+		sequence = fmt.Sprintf("\x1b]777;notify;%s;%s\x07", title, message)
+	default:
+		sequence = fmt.Sprintf("\x1b]9;%s: %s\x07", title, message)
+	}
+
+	if n.InTmux {
+		sequence = tmuxPassthrough(sequence)
+	}
+
+	return writeTerminal(n.Writer, sequence)
+}
+
+// sanitizeOSC removes characters that would terminate or split an OSC sequence
+func sanitizeOSC(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ';':
+			return ','
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		default:
+			return r
+		}
+	}, text)
+}
+
+// tmuxPassthrough wraps an escape sequence so tmux forwards it to the outer terminal
+func tmuxPassthrough(sequence string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// writeTerminal writes a control sequence to the terminal
+func writeTerminal(w io.Writer, sequence string) error {
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+
+	if _, err := io.WriteString(w, sequence); err != nil {
+		return fmt.Errorf("failed to write terminal notification: %w", err)
+	}
+	return nil
+}
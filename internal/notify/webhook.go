@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// Webhook payload formats
+const (
+	// PayloadJSON posts the full event as JSON
+	PayloadJSON = "json"
+	// PayloadSlack posts a Slack incoming webhook message
+	PayloadSlack = "slack"
+	// PayloadTeams posts a Microsoft Teams message card
+	PayloadTeams = "teams"
+)
+
+// Webhook request headers
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the request body
+	SignatureHeader = "X-GHMM-Signature-256"
+	// EventHeader carries the event type
+	EventHeader = "X-GHMM-Event"
+)
+
+// Webhook delivery defaults
+const (
+	defaultWebhookRetries = 3
+	defaultWebhookBackoff = time.Second
+	webhookRequestTimeout = 10 * time.Second
+)
+
+// WebhookNotifier posts migration events to an HTTP endpoint.
+//
+// Deliveries that fail with a network error, a 429 or a 5xx response are retried
+// with exponential backoff. When a secret is set the body is signed with
+// HMAC-SHA256 and the signature sent in the X-GHMM-Signature-256 header, in the
+// same "sha256=<hex>" form GitHub uses for its own webhooks.
+type WebhookNotifier struct {
+	URL     string
+	Secret  string
+	Format  string
+	Client  *http.Client
+	Retries int
+	Backoff time.Duration
+}
+
+// NewWebhookNotifier creates a notifier posting to url using the given payload format
+func NewWebhookNotifier(url, secret, format string) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		format = PayloadJSON
+	case PayloadJSON, PayloadSlack, PayloadTeams:
+	default:
+		return nil, fmt.Errorf("invalid webhook format %q (valid: %s, %s, %s)", format, PayloadJSON, PayloadSlack, PayloadTeams)
+	}
+
+	return &WebhookNotifier{
+		URL:     url,
+		Secret:  secret,
+		Format:  format,
+		Client:  &http.Client{Timeout: webhookRequestTimeout},
+		Retries: defaultWebhookRetries,
+		Backoff: defaultWebhookBackoff,
+	}, nil
+}
+
+// Notify implements Notifier.Notify
+func (n *WebhookNotifier) Notify(ctx context.Context, event services.Event) error {
+	body, err := n.payload(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	backoff := n.Backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.deliver(ctx, event, body)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= n.Retries {
+			return fmt.Errorf("webhook delivery to %s failed: %w", n.URL, err)
+		}
+
+		wait := backoff
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		backoff *= 2
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook delivery to %s failed: %w", n.URL, err)
+		case <-time.After(wait):
+		}
+	}
+}
+
+// deliver sends a single webhook request. It returns the delay requested by the
+// receiver through a Retry-After header, if any.
+func (n *WebhookNotifier) deliver(ctx context.Context, event services.Event, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gh-migration-monitor")
+	req.Header.Set(EventHeader, string(event.Type))
+	if n.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.Secret, body))
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("unexpected status %s", resp.Status)
+	default:
+		return 0, &permanentError{err: fmt.Errorf("unexpected status %s", resp.Status)}
+	}
+}
+
+// payload renders the request body for the notifier's format
+func (n *WebhookNotifier) payload(event services.Event) ([]byte, error) {
+	title, message := summarize(event)
+
+	switch n.Format {
+	case PayloadSlack:
+		return json.Marshal(map[string]string{
+			"text": fmt.Sprintf("*%s*\n%s", title, message),
+		})
+	case PayloadTeams:
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    title,
+			"title":      title,
+			"text":       message,
+			"themeColor": themeColor(event),
+		})
+	default:
+		return json.Marshal(event)
+	}
+}
+
+// Sign returns the "sha256=<hex>" HMAC-SHA256 signature of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// themeColor returns the Teams card accent color for an event
+func themeColor(event services.Event) string {
+	switch {
+	case event.Migration.State.IsFailed():
+		return "CF222E"
+	case event.Migration.State.IsSucceeded():
+		return "1A7F37"
+	default:
+		return "0969DA"
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// permanentError marks a delivery failure that retrying will not fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
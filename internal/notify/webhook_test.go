@@ -0,0 +1,251 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+)
+
+// webhookRequest is a request received by the test receiver
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver records the webhook requests it receives and answers them with
+// the given statuses in turn, repeating the last one
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []webhookRequest
+	statuses []int
+	header   http.Header
+}
+
+// newWebhookReceiver starts a receiver that is closed when the test ends
+func newWebhookReceiver(t *testing.T, header http.Header, statuses ...int) *webhookReceiver {
+	t.Helper()
+
+	r := &webhookReceiver{statuses: statuses, header: header}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) handle(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, webhookRequest{header: req.Header.Clone(), body: body})
+	status := http.StatusOK
+	if n := len(r.requests); len(r.statuses) > 0 {
+		status = r.statuses[min(n, len(r.statuses))-1]
+	}
+	r.mu.Unlock()
+
+	for name, values := range r.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(status)
+}
+
+// received returns the requests received so far
+func (r *webhookReceiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+// testNotifier creates a notifier for the receiver that retries without delay
+func testNotifier(t *testing.T, url, secret, format string) *WebhookNotifier {
+	t.Helper()
+
+	notifier, err := NewWebhookNotifier(url, secret, format)
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier: %v", err)
+	}
+	notifier.Backoff = time.Millisecond
+	return notifier
+}
+
+// failedEvent is a migration failing after it was in progress
+func failedEvent() services.Event {
+	previous := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	return services.Event{
+		Type:         services.EventStateChanged,
+		Organization: "org",
+		Migration: models.Migration{
+			ID:             "RM_1",
+			Organization:   "org",
+			RepositoryName: "api",
+			State:          models.StateFailed,
+			FailureReason:  "repository too large",
+		},
+		Previous:   &previous,
+		DetectedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifierPayloads(t *testing.T) {
+	const message = "org/api is FAILED (was IN_PROGRESS): repository too large"
+
+	tests := []struct {
+		format string
+		check  func(t *testing.T, body map[string]any)
+	}{
+		{
+			format: PayloadJSON,
+			check: func(t *testing.T, body map[string]any) {
+				migration, _ := body["migration"].(map[string]any)
+				previous, _ := body["previous"].(map[string]any)
+				if body["type"] != "state_changed" || body["organization"] != "org" ||
+					migration["id"] != "RM_1" || migration["state"] != "FAILED" || previous["state"] != "IN_PROGRESS" {
+					t.Errorf("JSON payload = %v, want the full event", body)
+				}
+			},
+		},
+		{
+			format: PayloadSlack,
+			check: func(t *testing.T, body map[string]any) {
+				if want := "*Migration failed*\n" + message; body["text"] != want {
+					t.Errorf("Slack text = %q, want %q", body["text"], want)
+				}
+			},
+		},
+		{
+			format: PayloadTeams,
+			check: func(t *testing.T, body map[string]any) {
+				if body["@type"] != "MessageCard" || body["title"] != "Migration failed" ||
+					body["text"] != message || body["themeColor"] != "CF222E" {
+					t.Errorf("Teams card = %v, want a red MessageCard for the failure", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			receiver := newWebhookReceiver(t, nil)
+			notifier := testNotifier(t, receiver.URL, "", tt.format)
+
+			if err := notifier.Notify(context.Background(), failedEvent()); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			requests := receiver.received()
+			if len(requests) != 1 {
+				t.Fatalf("received %d requests, want 1", len(requests))
+			}
+			if got := requests[0].header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			if got := requests[0].header.Get(EventHeader); got != "state_changed" {
+				t.Errorf("%s = %q, want state_changed", EventHeader, got)
+			}
+			if got := requests[0].header.Get(SignatureHeader); got != "" {
+				t.Errorf("unsigned request has %s %q", SignatureHeader, got)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(requests[0].body, &body); err != nil {
+				t.Fatalf("payload is not JSON: %v", err)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestWebhookNotifierSignsBody(t *testing.T) {
+	receiver := newWebhookReceiver(t, nil)
+	notifier := testNotifier(t, receiver.URL, "s3cret", PayloadJSON)
+
+	if err := notifier.Notify(context.Background(), failedEvent()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	requests := receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+
+	signature := requests[0].header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("%s = %q, want a sha256= signature", SignatureHeader, signature)
+	}
+	if want := Sign("s3cret", requests[0].body); signature != want {
+		t.Errorf("%s = %q, want %q for the received body", SignatureHeader, signature, want)
+	}
+	if Sign("other", requests[0].body) == signature {
+		t.Errorf("signature does not depend on the secret")
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		header   http.Header
+		wantErr  bool
+		want     int
+		minDelay time.Duration
+	}{
+		{
+			name:     "retries after a server error",
+			statuses: []int{http.StatusBadGateway, http.StatusOK},
+			want:     2,
+		},
+		{
+			name:     "waits for Retry-After",
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"1"}},
+			want:     2,
+			minDelay: time.Second,
+		},
+		{
+			name:     "gives up after the last attempt",
+			statuses: []int{http.StatusInternalServerError},
+			wantErr:  true,
+			want:     defaultWebhookRetries + 1,
+		},
+		{
+			name:     "does not retry a client error",
+			statuses: []int{http.StatusBadRequest},
+			wantErr:  true,
+			want:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := newWebhookReceiver(t, tt.header, tt.statuses...)
+			notifier := testNotifier(t, receiver.URL, "", PayloadJSON)
+
+			start := time.Now()
+			err := notifier.Notify(context.Background(), failedEvent())
+			elapsed := time.Since(start)
+
+			if tt.wantErr && err == nil {
+				t.Errorf("Notify succeeded, want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Notify: %v", err)
+			}
+			if got := len(receiver.received()); got != tt.want {
+				t.Errorf("received %d requests, want %d", got, tt.want)
+			}
+			if elapsed < tt.minDelay {
+				t.Errorf("Notify returned after %s, want at least %s", elapsed, tt.minDelay)
+			}
+		})
+	}
+}
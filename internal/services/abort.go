@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// AbortResult is the outcome of aborting one migration
+type AbortResult struct {
+	Migration models.Migration
+	Err       error
+}
+
+// CheckAbortable returns an error explaining why the migration cannot be aborted.
+// Only queued and in-progress GEI migrations can be aborted.
+func CheckAbortable(migration models.Migration) error {
+	switch {
+	case !migration.State.IsQueued() && !migration.State.IsInProgress():
+		return fmt.Errorf("%s is %s, only queued and in-progress migrations can be aborted", migration.RepositoryName, migration.State)
+	case migration.MigrationSource.ID == "":
+		return fmt.Errorf("%s has no migration source, only GEI migrations can be aborted", migration.RepositoryName)
+	}
+	return nil
+}
+
+// AbortMigrations aborts each migration one at a time and returns the results in
+// order. With dryRun set the migrations are only checked.
+func (s *migrationService) AbortMigrations(ctx context.Context, migrations []models.Migration, dryRun bool) []AbortResult {
+	results := make([]AbortResult, len(migrations))
+
+	for i, migration := range migrations {
+		results[i] = AbortResult{Migration: migration}
+
+		if err := CheckAbortable(migration); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if dryRun {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if err := s.githubClient.AbortRepositoryMigration(ctx, migration.ID); err != nil {
+			results[i].Err = fmt.Errorf("failed to abort migration: %w", err)
+		}
+	}
+
+	return results
+}
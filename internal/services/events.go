@@ -0,0 +1,212 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// EventType identifies the kind of change detected between two snapshots
+type EventType string
+
+const (
+	// EventMigrationAdded is emitted when a migration appears, for the first time or
+	// again after it was missing from a snapshot
+	EventMigrationAdded EventType = "migration_added"
+	// EventStateChanged is emitted when a migration moves to a different state
+	EventStateChanged EventType = "state_changed"
+	// EventMigrationRemoved is emitted when a migration is no longer returned by the API
+	EventMigrationRemoved EventType = "migration_removed"
+	// EventFailureReasonChanged is emitted when the failure reason changes without a state change
+	EventFailureReasonChanged EventType = "failure_reason_changed"
+	// EventMigrationUpdated is emitted when any other field changes, e.g. the warnings count
+	EventMigrationUpdated EventType = "migration_updated"
+)
+
+// Event describes a change to a migration between consecutive snapshots. Previous
+// is the migration before the change; for an added migration it is only set when
+// the migration reappears, to its last known version.
+type Event struct {
+	Type         EventType         `json:"type"`
+	Organization string            `json:"organization"`
+	Migration    models.Migration  `json:"migration"`
+	Previous     *models.Migration `json:"previous,omitempty"`
+	DetectedAt   time.Time         `json:"detected_at"`
+}
+
+// PreviousState returns the state before the change, or an empty state for new migrations
+func (e Event) PreviousState() models.State {
+	if e.Previous == nil {
+		return ""
+	}
+	return e.Previous.State
+}
+
+// ChangesState returns true if the event reports a migration entering its state:
+// a state change, a new migration, or a migration that reappears in a different
+// state than it was last seen in
+func (e Event) ChangesState() bool {
+	switch e.Type {
+	case EventStateChanged:
+		return true
+	case EventMigrationAdded:
+		return e.Previous == nil || e.Previous.State != e.Migration.State
+	default:
+		return false
+	}
+}
+
+// IsCompletion returns true if the event reports a migration reaching a terminal
+// state, either by changing state or by appearing already finished. A migration
+// that reappears in the state it was last seen in does not complete again.
+func (e Event) IsCompletion() bool {
+	return e.ChangesState() && (e.Migration.State.IsSucceeded() || e.Migration.State.IsFailed())
+}
+
+// ChangeDetector compares consecutive migration snapshots per organization and
+// notifies subscribers of every change it finds.
+//
+// The first snapshot observed for an organization only establishes a baseline and
+// does not emit events, so subscribers are not flooded with every existing migration
+// when monitoring starts. Migrations that are missing from a snapshot, e.g. after a
+// failed query, are remembered, so their return is reported against their last
+// known version.
+type ChangeDetector struct {
+	mu          sync.Mutex
+	snapshots   map[string][]models.Migration
+	known       map[string]map[string]models.Migration
+	subscribers []func(Event)
+}
+
+// NewChangeDetector creates a new change detector
+func NewChangeDetector() *ChangeDetector {
+	return &ChangeDetector{
+		snapshots: make(map[string][]models.Migration),
+		known:     make(map[string]map[string]models.Migration),
+	}
+}
+
+// Subscribe registers a handler that is called synchronously, in subscription
+// order, for every event detected by Observe
+func (d *ChangeDetector) Subscribe(handler func(Event)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribers = append(d.subscribers, handler)
+}
+
+// Observe records a new snapshot of an organization's migrations, notifies
+// subscribers of the changes since the previous snapshot and returns them
+func (d *ChangeDetector) Observe(org string, migrations []models.Migration) []Event {
+	d.mu.Lock()
+	previous, seen := d.snapshots[org]
+	d.snapshots[org] = append([]models.Migration(nil), migrations...)
+	subscribers := make([]func(Event), len(d.subscribers))
+	copy(subscribers, d.subscribers)
+
+	known := d.known[org]
+	if known == nil {
+		known = make(map[string]models.Migration)
+		d.known[org] = known
+	}
+
+	var events []Event
+	if seen {
+		events = DiffSnapshots(org, previous, migrations, time.Now())
+		for i := range events {
+			if last, ok := known[snapshotKey(events[i].Migration)]; ok && events[i].Type == EventMigrationAdded {
+				events[i].Previous = &last
+			}
+		}
+	}
+	for _, migration := range migrations {
+		known[snapshotKey(migration)] = migration
+	}
+	d.mu.Unlock()
+
+	if !seen {
+		return nil
+	}
+
+	for _, event := range events {
+		for _, handler := range subscribers {
+			handler(event)
+		}
+	}
+
+	return events
+}
+
+// Snapshot returns the last snapshot observed for an organization
+func (d *ChangeDetector) Snapshot(org string) ([]models.Migration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	migrations, ok := d.snapshots[org]
+	return append([]models.Migration(nil), migrations...), ok
+}
+
+// DiffSnapshots returns the events that describe how an organization's migrations
+// changed from the previous snapshot to the current one
+func DiffSnapshots(org string, previous, current []models.Migration, detectedAt time.Time) []Event {
+	previousByKey := make(map[string]models.Migration, len(previous))
+	for _, migration := range previous {
+		previousByKey[snapshotKey(migration)] = migration
+	}
+
+	var events []Event
+	currentKeys := make(map[string]bool, len(current))
+
+	for _, migration := range current {
+		key := snapshotKey(migration)
+		currentKeys[key] = true
+
+		old, existed := previousByKey[key]
+		event := Event{
+			Organization: org,
+			Migration:    migration,
+			DetectedAt:   detectedAt,
+		}
+
+		switch {
+		case !existed:
+			event.Type = EventMigrationAdded
+		case old.State != migration.State:
+			event.Type = EventStateChanged
+		case old.FailureReason != migration.FailureReason:
+			event.Type = EventFailureReasonChanged
+		case !old.Equal(migration):
+			event.Type = EventMigrationUpdated
+		default:
+			continue
+		}
+
+		if existed {
+			event.Previous = &old
+		}
+		events = append(events, event)
+	}
+
+	for _, migration := range previous {
+		if currentKeys[snapshotKey(migration)] {
+			continue
+		}
+		removed := migration
+		events = append(events, Event{
+			Type:         EventMigrationRemoved,
+			Organization: org,
+			Migration:    migration,
+			Previous:     &removed,
+			DetectedAt:   detectedAt,
+		})
+	}
+
+	return events
+}
+
+// snapshotKey identifies a migration across snapshots. Legacy migrations share one
+// ID across several repositories, so the repository name is part of the key.
+func snapshotKey(migration models.Migration) string {
+	return migration.ID + "\x00" + migration.RepositoryName
+}
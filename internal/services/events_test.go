@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// describeEvents describes events as "type:id/repository from->to"
+func describeEvents(events []Event) string {
+	var described []string
+	for _, event := range events {
+		described = append(described, fmt.Sprintf("%s:%s/%s %s->%s", event.Type,
+			event.Migration.ID, event.Migration.RepositoryName, event.PreviousState(), event.Migration.State))
+	}
+	return strings.Join(described, " ")
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	queued := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateQueued}
+	running := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	failed := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateFailed, FailureReason: "timeout"}
+	retried := failed
+	retried.FailureReason = "archive too large"
+	warned := running
+	warned.WarningsCount = 2
+
+	tests := []struct {
+		name     string
+		previous []models.Migration
+		current  []models.Migration
+		want     string
+	}{
+		{
+			name:    "added",
+			current: []models.Migration{queued},
+			want:    "migration_added:RM_1/api ->QUEUED",
+		},
+		{
+			name:     "state changed",
+			previous: []models.Migration{running},
+			current:  []models.Migration{failed},
+			want:     "state_changed:RM_1/api IN_PROGRESS->FAILED",
+		},
+		{
+			name:     "failure reason changed",
+			previous: []models.Migration{failed},
+			current:  []models.Migration{retried},
+			want:     "failure_reason_changed:RM_1/api FAILED->FAILED",
+		},
+		{
+			name:     "updated",
+			previous: []models.Migration{running},
+			current:  []models.Migration{warned},
+			want:     "migration_updated:RM_1/api IN_PROGRESS->IN_PROGRESS",
+		},
+		{
+			name:     "removed",
+			previous: []models.Migration{running},
+			want:     "migration_removed:RM_1/api IN_PROGRESS->IN_PROGRESS",
+		},
+		{
+			name:     "unchanged",
+			previous: []models.Migration{running},
+			current:  []models.Migration{running},
+			want:     "",
+		},
+		{
+			name: "legacy rows sharing an ID",
+			previous: []models.Migration{
+				{ID: "1", RepositoryName: "https://ghes/org/api", State: "exporting"},
+				{ID: "1", RepositoryName: "https://ghes/org/web", State: "exporting"},
+			},
+			current: []models.Migration{
+				{ID: "1", RepositoryName: "https://ghes/org/api", State: "exported"},
+				{ID: "1", RepositoryName: "https://ghes/org/docs", State: "exported"},
+			},
+			want: "state_changed:1/https://ghes/org/api exporting->exported " +
+				"migration_added:1/https://ghes/org/docs ->exported " +
+				"migration_removed:1/https://ghes/org/web exporting->exporting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			events := DiffSnapshots("org", tt.previous, tt.current, detectedAt)
+			if got := describeEvents(events); got != tt.want {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+			for _, event := range events {
+				if event.Organization != "org" || !event.DetectedAt.Equal(detectedAt) {
+					t.Errorf("event %+v is not for org at %s", event, detectedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestChangeDetectorReappearingMigration(t *testing.T) {
+	running := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateInProgress}
+	failed := models.Migration{ID: "RM_1", RepositoryName: "api", State: models.StateFailed}
+	other := models.Migration{ID: "RM_2", RepositoryName: "web", State: models.StateSucceeded}
+
+	detector := NewChangeDetector()
+	var completions []string
+	detector.Subscribe(func(event Event) {
+		if event.IsCompletion() {
+			completions = append(completions, event.Migration.ID+"="+string(event.Migration.State))
+		}
+	})
+
+	steps := []struct {
+		name       string
+		migrations []models.Migration
+		want       string
+	}{
+		{"baseline", []models.Migration{running}, ""},
+		{"fails", []models.Migration{failed}, "RM_1=FAILED"},
+		{"missing for one refresh", nil, ""},
+		{"reappears unchanged", []models.Migration{failed}, ""},
+		{"new finished migration", []models.Migration{failed, other}, "RM_2=SUCCEEDED"},
+		{"missing again", []models.Migration{failed}, ""},
+		{"reappears finished", []models.Migration{failed, other}, ""},
+	}
+
+	for _, step := range steps {
+		completions = nil
+		detector.Observe("org", step.migrations)
+		if got := strings.Join(completions, " "); got != step.want {
+			t.Errorf("%s: completions = %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	// A migration that finished while it was missing still completes
+	detector = NewChangeDetector()
+	detector.Subscribe(func(event Event) {
+		if event.IsCompletion() {
+			completions = append(completions, event.Migration.ID+"="+string(event.Migration.State))
+		}
+	})
+	completions = nil
+	detector.Observe("org", []models.Migration{running})
+	detector.Observe("org", nil)
+	detector.Observe("org", []models.Migration{failed})
+	if got := strings.Join(completions, " "); got != "RM_1=FAILED" {
+		t.Errorf("completions = %q, want RM_1=FAILED", got)
+	}
+}
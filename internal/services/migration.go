@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/mona-actions/gh-migration-monitor/internal/api"
 	"github.com/mona-actions/gh-migration-monitor/internal/models"
@@ -10,7 +11,13 @@ import (
 
 // MigrationService handles migration-related business logic
 type MigrationService interface {
-	ListMigrations(ctx context.Context, org string, isLegacy bool) (*models.MigrationSummary, error)
+	ListMigrations(ctx context.Context, opts models.ListOptions, isLegacy bool) (*models.MigrationSummary, error)
+	ListOrganizationsMigrations(ctx context.Context, orgs []string, opts models.ListOptions, isLegacy bool) []OrganizationResult
+	ListEnterpriseOrganizations(ctx context.Context, enterprise string) ([]string, error)
+	RetryMigrations(ctx context.Context, migrations []models.Migration, opts RetryOptions) []RetryResult
+	AbortMigrations(ctx context.Context, migrations []models.Migration, dryRun bool) []AbortResult
+	GetMigration(ctx context.Context, id string) (models.Migration, error)
+	DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error
 }
 
 // migrationService implements MigrationService
@@ -25,32 +32,33 @@ func NewMigrationService(githubClient api.GitHubClient) MigrationService {
 	}
 }
 
-// ListMigrations retrieves and categorizes migrations by state
-func (s *migrationService) ListMigrations(ctx context.Context, org string, isLegacy bool) (*models.MigrationSummary, error) {
-	migrations, err := s.githubClient.ListMigrations(ctx, org, isLegacy)
+// ListMigrations retrieves the organization's migrations selected by opts and
+// categorizes them by state. Migrations outside the time window of the options
+// are dropped even if the client returned them.
+func (s *migrationService) ListMigrations(ctx context.Context, opts models.ListOptions, isLegacy bool) (*models.MigrationSummary, error) {
+	migrations, err := s.githubClient.ListMigrations(ctx, opts, isLegacy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list migrations: %w", err)
 	}
+	migrations = filterCreatedBetween(migrations, opts.Since, opts.Until)
 
-	summary := &models.MigrationSummary{
-		Queued:     make([]models.Migration, 0),
-		InProgress: make([]models.Migration, 0),
-		Succeeded:  make([]models.Migration, 0),
-		Failed:     make([]models.Migration, 0),
+	for i := range migrations {
+		migrations[i].Organization = opts.Organization
 	}
 
-	for _, migration := range migrations {
-		switch {
-		case migration.State.IsQueued():
-			summary.Queued = append(summary.Queued, migration)
-		case migration.State.IsInProgress():
-			summary.InProgress = append(summary.InProgress, migration)
-		case migration.State.IsSucceeded():
-			summary.Succeeded = append(summary.Succeeded, migration)
-		case migration.State.IsFailed():
-			summary.Failed = append(summary.Failed, migration)
-		}
+	return models.NewMigrationSummary(migrations), nil
+}
+
+// GetMigration retrieves a single GEI migration by ID
+func (s *migrationService) GetMigration(ctx context.Context, id string) (models.Migration, error) {
+	migration, err := s.githubClient.GetMigration(ctx, id)
+	if err != nil {
+		return models.Migration{}, fmt.Errorf("failed to get migration: %w", err)
 	}
+	return migration, nil
+}
 
-	return summary, nil
+// DownloadMigrationLog writes the contents of a migration log to w
+func (s *migrationService) DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error {
+	return s.githubClient.DownloadMigrationLog(ctx, logURL, w)
 }
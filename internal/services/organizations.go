@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// OrganizationResult holds the migrations listed for one organization
+type OrganizationResult struct {
+	Organization string
+	Summary      *models.MigrationSummary
+	Err          error
+}
+
+// organizationTimeout bounds the listing of a single organization, so one slow
+// organization cannot use up the time of the organizations queued behind it
+var organizationTimeout = time.Minute
+
+// ListOrganizationsMigrations retrieves the migrations of several organizations
+// concurrently, selected by opts with the organization set for each of them.
+// Results are returned in the order of orgs, and a failure for one organization
+// does not affect the others. Each organization gets its own timeout, which
+// starts when its listing does.
+func (s *migrationService) ListOrganizationsMigrations(ctx context.Context, orgs []string, opts models.ListOptions, isLegacy bool) []OrganizationResult {
+	results := make([]OrganizationResult, len(orgs))
+	for i, org := range orgs {
+		// Organizations skipped because the context ended keep this error
+		results[i] = OrganizationResult{Organization: org, Err: fmt.Errorf("%s: %w", org, context.Canceled)}
+	}
+
+	api.ForEach(ctx, len(orgs), api.DefaultConcurrency, func(ctx context.Context, i int) {
+		orgCtx, cancel := context.WithTimeout(ctx, organizationTimeout)
+		defer cancel()
+
+		orgOpts := opts
+		orgOpts.Organization = orgs[i]
+		summary, err := s.ListMigrations(orgCtx, orgOpts, isLegacy)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", orgs[i], err)
+		}
+		results[i] = OrganizationResult{Organization: orgs[i], Summary: summary, Err: err}
+	})
+
+	return results
+}
+
+// ListEnterpriseOrganizations retrieves the organizations belonging to an enterprise
+func (s *migrationService) ListEnterpriseOrganizations(ctx context.Context, enterprise string) ([]string, error) {
+	organizations, err := s.githubClient.ListEnterpriseOrganizations(ctx, enterprise)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enterprise organizations: %w", err)
+	}
+	return organizations, nil
+}
+
+// MergeResults combines the migrations of every organization that was listed
+// successfully into one summary, and joins the errors of those that were not
+func MergeResults(results []OrganizationResult) (*models.MigrationSummary, error) {
+	var migrations []models.Migration
+	var errs []error
+
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		migrations = append(migrations, result.Summary.All()...)
+	}
+
+	return models.NewMigrationSummary(migrations), errors.Join(errs...)
+}
+
+// OrganizationResolver determines the organizations to monitor. When an enterprise
+// is set its organizations are rediscovered on every call, so organizations created
+// during a migration wave are picked up automatically.
+type OrganizationResolver struct {
+	service       MigrationService
+	organizations []string
+	enterprise    string
+
+	mu   sync.Mutex
+	last []string
+}
+
+// NewOrganizationResolver creates a resolver for the configured organizations and
+// the optional enterprise
+func NewOrganizationResolver(service MigrationService, organizations []string, enterprise string) *OrganizationResolver {
+	return &OrganizationResolver{
+		service:       service,
+		organizations: organizations,
+		enterprise:    enterprise,
+		last:          organizations,
+	}
+}
+
+// Resolve returns the organizations to monitor. If the enterprise's organizations
+// cannot be listed, the last known organizations are returned along with the error.
+func (r *OrganizationResolver) Resolve(ctx context.Context) ([]string, error) {
+	if r.enterprise == "" {
+		return r.organizations, nil
+	}
+
+	discovered, err := r.service.ListEnterpriseOrganizations(ctx, r.enterprise)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		return r.last, err
+	}
+
+	r.last = mergeOrganizations(r.organizations, discovered)
+	return r.last, nil
+}
+
+// mergeOrganizations appends the discovered organizations to the configured ones,
+// skipping duplicates regardless of case
+func mergeOrganizations(configured, discovered []string) []string {
+	seen := make(map[string]bool, len(configured)+len(discovered))
+	merged := make([]string, 0, len(configured)+len(discovered))
+
+	for _, org := range append(append([]string(nil), configured...), discovered...) {
+		if seen[strings.ToLower(org)] {
+			continue
+		}
+		seen[strings.ToLower(org)] = true
+		merged = append(merged, org)
+	}
+	return merged
+}
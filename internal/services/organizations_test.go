@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+func TestListOrganizationsMigrationsTimesOutPerOrganization(t *testing.T) {
+	defer func(timeout time.Duration) { organizationTimeout = timeout }(organizationTimeout)
+	organizationTimeout = 50 * time.Millisecond
+
+	// The slow organization never answers, the others answer within their own timeout
+	// even though together they take longer than one
+	client := &fakeGitHubClient{listMigrations: func(ctx context.Context, opts models.ListOptions) ([]models.Migration, error) {
+		if opts.Organization == "slow" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		select {
+		case <-time.After(30 * time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return []models.Migration{{ID: "RM_" + opts.Organization, RepositoryName: "api", State: models.StateSucceeded}}, nil
+	}}
+
+	orgs := []string{"slow", "a", "b", "c", "d", "e", "f"}
+	results := NewMigrationService(client).ListOrganizationsMigrations(context.Background(), orgs, models.ListOptions{}, false)
+
+	if len(results) != len(orgs) {
+		t.Fatalf("got %d results, want %d", len(results), len(orgs))
+	}
+	if !errors.Is(results[0].Err, context.DeadlineExceeded) {
+		t.Errorf("slow organization error = %v, want a deadline exceeded error", results[0].Err)
+	}
+	for i, result := range results[1:] {
+		if result.Organization != orgs[i+1] {
+			t.Errorf("results[%d] is for %s, want %s", i+1, result.Organization, orgs[i+1])
+		}
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Organization, result.Err)
+			continue
+		}
+		if migrations := result.Summary.All(); len(migrations) != 1 || migrations[0].Organization != result.Organization {
+			t.Errorf("%s: got migrations %+v", result.Organization, migrations)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// RetryOptions configures how failed migrations are retried
+type RetryOptions struct {
+	// SourceToken grants access to the source repositories. The GitHub token is
+	// used when it is empty.
+	SourceToken string
+	// DryRun checks the migrations without starting any new migration
+	DryRun bool
+}
+
+// RetryResult is the outcome of retrying one failed migration
+type RetryResult struct {
+	Original models.Migration
+	// Migration is the newly started migration, nil on a dry run or when the retry failed
+	Migration *models.Migration
+	Err       error
+}
+
+// CheckRetryable returns an error explaining why the migration cannot be retried.
+//
+// Only failed GEI migrations that know their migration source and source URL can be
+// re-issued. When all is given, a migration whose repository already has a more
+// recent migration is rejected as well, so the same failure is not retried twice.
+func CheckRetryable(migration models.Migration, all []models.Migration) error {
+	switch {
+	case !migration.State.IsFailed():
+		return fmt.Errorf("%s is %s, only failed migrations can be retried", migration.RepositoryName, migration.State)
+	case migration.MigrationSource.ID == "":
+		return fmt.Errorf("%s has no migration source, only GEI migrations can be retried", migration.RepositoryName)
+	case migration.SourceURL == "":
+		return fmt.Errorf("%s has no source URL", migration.RepositoryName)
+	case migration.Organization == "":
+		return fmt.Errorf("%s has no target organization", migration.RepositoryName)
+	}
+
+	if newer, ok := newerMigration(migration, all); ok {
+		return fmt.Errorf("%s already has a newer migration %s (%s)", migration.RepositoryName, newer.ID, newer.State)
+	}
+
+	return nil
+}
+
+// LatestFailed returns the migrations that failed and are the most recent
+// migration of their repository
+func LatestFailed(migrations []models.Migration) []models.Migration {
+	var failed []models.Migration
+	for _, migration := range migrations {
+		if _, ok := newerMigration(migration, migrations); migration.State.IsFailed() && !ok {
+			failed = append(failed, migration)
+		}
+	}
+	return failed
+}
+
+// RetryMigrations re-issues each failed migration with its original migration
+// source, source URL and target repository. A migration superseded by a newer
+// migration of its repository in the same batch is not retried. Migrations are
+// started one at a time to stay clear of secondary rate limits, and results are
+// returned in order.
+func (s *migrationService) RetryMigrations(ctx context.Context, migrations []models.Migration, opts RetryOptions) []RetryResult {
+	results := make([]RetryResult, len(migrations))
+
+	for i, migration := range migrations {
+		results[i] = RetryResult{Original: migration}
+
+		if err := CheckRetryable(migration, migrations); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		started, err := s.githubClient.StartRepositoryMigration(ctx, api.StartMigrationInput{
+			Organization:    migration.Organization,
+			RepositoryName:  migration.RepositoryName,
+			SourceID:        migration.MigrationSource.ID,
+			SourceURL:       migration.SourceURL,
+			ContinueOnError: migration.ContinueOnError,
+			SourceToken:     opts.SourceToken,
+		})
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to retry migration: %w", err)
+			continue
+		}
+		results[i].Migration = &started
+	}
+
+	return results
+}
+
+// newerMigration returns a migration of the same repository that was created later, if any
+func newerMigration(migration models.Migration, migrations []models.Migration) (models.Migration, bool) {
+	for _, other := range migrations {
+		if other.ID != migration.ID && sameRepository(other, migration) && other.CreatedAt.After(migration.CreatedAt) {
+			return other, true
+		}
+	}
+	return models.Migration{}, false
+}
+
+// sameRepository returns true if both migrations target the same repository
+func sameRepository(a, b models.Migration) bool {
+	return strings.EqualFold(a.Organization, b.Organization) && strings.EqualFold(a.RepositoryName, b.RepositoryName)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/api"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// fakeGitHubClient is an api.GitHubClient that lists migrations with listMigrations,
+// records the migrations it is asked to start and fails for the repositories in
+// startErrors
+type fakeGitHubClient struct {
+	listMigrations func(ctx context.Context, opts models.ListOptions) ([]models.Migration, error)
+
+	mu          sync.Mutex
+	started     []api.StartMigrationInput
+	startErrors map[string]error
+}
+
+func (c *fakeGitHubClient) ListMigrations(ctx context.Context, opts models.ListOptions, isLegacy bool) ([]models.Migration, error) {
+	if c.listMigrations == nil {
+		return nil, nil
+	}
+	return c.listMigrations(ctx, opts)
+}
+
+func (c *fakeGitHubClient) GetMigration(ctx context.Context, id string) (models.Migration, error) {
+	return models.Migration{}, errors.New("not found")
+}
+
+func (c *fakeGitHubClient) DownloadMigrationLog(ctx context.Context, logURL string, w io.Writer) error {
+	return nil
+}
+
+func (c *fakeGitHubClient) ListEnterpriseOrganizations(ctx context.Context, enterprise string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *fakeGitHubClient) StartRepositoryMigration(ctx context.Context, input api.StartMigrationInput) (models.Migration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.started = append(c.started, input)
+	if err := c.startErrors[input.RepositoryName]; err != nil {
+		return models.Migration{}, err
+	}
+	return models.Migration{
+		ID:             "RM_new_" + input.RepositoryName,
+		Organization:   input.Organization,
+		RepositoryName: input.RepositoryName,
+		State:          models.StateQueued,
+	}, nil
+}
+
+func (c *fakeGitHubClient) AbortRepositoryMigration(ctx context.Context, migrationID string) error {
+	return nil
+}
+
+// failedGEIMigration is a failed GEI migration that can be retried
+func failedGEIMigration(id, repository string, createdAt time.Time) models.Migration {
+	return models.Migration{
+		ID:              id,
+		Organization:    "org",
+		RepositoryName:  repository,
+		State:           models.StateFailed,
+		CreatedAt:       createdAt,
+		SourceURL:       "https://source.example.com/team/" + repository,
+		ContinueOnError: true,
+		MigrationSource: models.MigrationSource{ID: "MS_1", Name: "GHES"},
+	}
+}
+
+func TestRetryMigrations(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	superseded := failedGEIMigration("RM_1", "api", created)
+	newer := failedGEIMigration("RM_2", "api", created.Add(time.Hour))
+	broken := failedGEIMigration("RM_3", "broken", created)
+	web := failedGEIMigration("RM_4", "web", created)
+	web.ContinueOnError = false
+	succeeded := failedGEIMigration("RM_5", "docs", created)
+	succeeded.State = models.StateSucceeded
+	legacy := failedGEIMigration("RM_6", "legacy", created)
+	legacy.MigrationSource = models.MigrationSource{}
+
+	migrations := []models.Migration{superseded, newer, broken, web, succeeded, legacy}
+
+	client := &fakeGitHubClient{startErrors: map[string]error{"broken": errors.New("source unavailable")}}
+	service := NewMigrationService(client)
+
+	results := service.RetryMigrations(context.Background(), migrations, RetryOptions{SourceToken: "source-token"})
+	if len(results) != len(migrations) {
+		t.Fatalf("got %d results, want %d", len(results), len(migrations))
+	}
+
+	// Only the latest failed GEI migration of each repository is started, and a
+	// failure for one does not stop the others
+	wantStarted := []api.StartMigrationInput{
+		{Organization: "org", RepositoryName: "api", SourceID: "MS_1", SourceURL: "https://source.example.com/team/api", ContinueOnError: true, SourceToken: "source-token"},
+		{Organization: "org", RepositoryName: "broken", SourceID: "MS_1", SourceURL: "https://source.example.com/team/broken", ContinueOnError: true, SourceToken: "source-token"},
+		{Organization: "org", RepositoryName: "web", SourceID: "MS_1", SourceURL: "https://source.example.com/team/web", ContinueOnError: false, SourceToken: "source-token"},
+	}
+	if len(client.started) != len(wantStarted) {
+		t.Fatalf("started %d migrations, want %d: %+v", len(client.started), len(wantStarted), client.started)
+	}
+	for i, want := range wantStarted {
+		if client.started[i] != want {
+			t.Errorf("started[%d] = %+v, want %+v", i, client.started[i], want)
+		}
+	}
+
+	for i, result := range results {
+		if result.Original.ID != migrations[i].ID {
+			t.Errorf("results[%d] is for %s, want %s", i, result.Original.ID, migrations[i].ID)
+		}
+	}
+
+	tests := []struct {
+		index   int
+		wantErr bool
+	}{
+		{0, true},  // superseded by RM_2
+		{1, false}, // retried
+		{2, true},  // the mutation failed
+		{3, false}, // retried after the failure
+		{4, true},  // not failed
+		{5, true},  // no migration source
+	}
+	for _, tt := range tests {
+		result := results[tt.index]
+		if tt.wantErr && (result.Err == nil || result.Migration != nil) {
+			t.Errorf("%s: got migration %v and error %v, want only an error", result.Original.ID, result.Migration, result.Err)
+		}
+		if !tt.wantErr && (result.Err != nil || result.Migration == nil) {
+			t.Errorf("%s: got migration %v and error %v, want a new migration", result.Original.ID, result.Migration, result.Err)
+		}
+	}
+}
+
+func TestRetryMigrationsDryRun(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	migrations := []models.Migration{
+		failedGEIMigration("RM_1", "api", created),
+		failedGEIMigration("RM_2", "web", created),
+	}
+
+	client := &fakeGitHubClient{}
+	results := NewMigrationService(client).RetryMigrations(context.Background(), migrations, RetryOptions{DryRun: true})
+
+	if len(client.started) != 0 {
+		t.Errorf("dry run started %d migrations", len(client.started))
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Migration != nil {
+			t.Errorf("%s: got migration %v and error %v, want neither", result.Original.ID, result.Migration, result.Err)
+		}
+	}
+}
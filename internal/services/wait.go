@@ -0,0 +1,149 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// WaitTarget selects the migrations a wait applies to. An empty target selects
+// every migration in the monitored organizations.
+//
+// Repositories may be qualified as "org/repo" to tell apart repositories with the
+// same name in different organizations.
+type WaitTarget struct {
+	Repositories []string
+	MigrationIDs []string
+}
+
+// IsEmpty returns true if the target does not name any repositories or migrations
+func (t WaitTarget) IsEmpty() bool {
+	return len(t.Repositories) == 0 && len(t.MigrationIDs) == 0
+}
+
+// WaitStatus reports how far the targeted migrations are from a terminal state
+type WaitStatus struct {
+	Total      int
+	Queued     int
+	InProgress int
+	Succeeded  int
+	Failed     int
+	// Missing counts named repositories or migration IDs that have no migration yet
+	Missing int
+	// FailedMigrations holds the targeted migrations that ended in a failed state
+	FailedMigrations []models.Migration
+}
+
+// Done returns true once every targeted migration has succeeded or failed
+func (s WaitStatus) Done() bool {
+	return s.Missing == 0 && s.Queued == 0 && s.InProgress == 0
+}
+
+// Completed returns the number of migrations in a terminal state
+func (s WaitStatus) Completed() int {
+	return s.Succeeded + s.Failed
+}
+
+// EvaluateWait computes the wait status of the targeted migrations in the summary.
+//
+// When a repository has been migrated more than once, only its most recent
+// migration is considered, whether the target names it or is empty, so that a
+// successful retry supersedes an earlier failure.
+func EvaluateWait(summary *models.MigrationSummary, target WaitTarget) WaitStatus {
+	var selected []models.Migration
+	status := WaitStatus{}
+
+	if target.IsEmpty() {
+		selected = latestMigrations(summary.All())
+	} else {
+		var missing []string
+		selected, missing = SelectMigrations(summary.All(), target)
+		status.Missing = len(missing)
+	}
+
+	for _, migration := range selected {
+		switch {
+		case migration.State.IsQueued(), migration.State.IsNotMigrated():
+			// Migrations that have not started yet are still pending
+			status.Queued++
+		case migration.State.IsInProgress():
+			status.InProgress++
+		case migration.State.IsSucceeded():
+			status.Succeeded++
+		case migration.State.IsFailed():
+			status.Failed++
+			status.FailedMigrations = append(status.FailedMigrations, migration)
+		}
+	}
+
+	status.Total = len(selected) + status.Missing
+	return status
+}
+
+// latestMigrations returns the most recent migration of every repository, in the
+// order the repositories first appear
+func latestMigrations(migrations []models.Migration) []models.Migration {
+	index := make(map[string]int, len(migrations))
+	var latest []models.Migration
+
+	for _, migration := range migrations {
+		key := strings.ToLower(migration.Organization + "/" + migration.RepositoryName)
+		if i, ok := index[key]; ok {
+			if migration.CreatedAt.After(latest[i].CreatedAt) {
+				latest[i] = migration
+			}
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, migration)
+	}
+
+	return latest
+}
+
+// SelectMigrations returns the migrations named by the target, using the most
+// recent migration of each named repository, and the names that had no match
+func SelectMigrations(migrations []models.Migration, target WaitTarget) ([]models.Migration, []string) {
+	byID := make(map[string]models.Migration, len(migrations))
+	latestByRepo := make(map[string]models.Migration, len(migrations))
+
+	for _, migration := range migrations {
+		byID[migration.ID] = migration
+
+		keys := []string{strings.ToLower(migration.RepositoryName)}
+		if migration.Organization != "" {
+			keys = append(keys, strings.ToLower(migration.Organization+"/"+migration.RepositoryName))
+		}
+		for _, key := range keys {
+			if existing, ok := latestByRepo[key]; !ok || migration.CreatedAt.After(existing.CreatedAt) {
+				latestByRepo[key] = migration
+			}
+		}
+	}
+
+	var selected []models.Migration
+	var missing []string
+	seen := make(map[string]bool)
+
+	add := func(name string, migration models.Migration, ok bool) {
+		if !ok {
+			missing = append(missing, name)
+			return
+		}
+		if !seen[migration.ID] {
+			seen[migration.ID] = true
+			selected = append(selected, migration)
+		}
+	}
+
+	for _, id := range target.MigrationIDs {
+		migration, ok := byID[id]
+		add(id, migration, ok)
+	}
+	for _, repo := range target.Repositories {
+		migration, ok := latestByRepo[strings.ToLower(repo)]
+		add(repo, migration, ok)
+	}
+
+	return selected, missing
+}
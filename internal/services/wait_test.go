@@ -0,0 +1,51 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+func TestEvaluateWaitUsesLatestMigrationOfEveryRepository(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary := models.NewMigrationSummary([]models.Migration{
+		{ID: "RM_1", Organization: "org", RepositoryName: "api", State: models.StateFailed, CreatedAt: created},
+		{ID: "RM_2", Organization: "org", RepositoryName: "api", State: models.StateSucceeded, CreatedAt: created.Add(time.Hour)},
+		{ID: "RM_3", Organization: "org", RepositoryName: "web", State: models.StateSucceeded, CreatedAt: created},
+		{ID: "RM_4", Organization: "other", RepositoryName: "api", State: models.StateInProgress, CreatedAt: created},
+	})
+
+	tests := []struct {
+		name   string
+		target WaitTarget
+		want   WaitStatus
+	}{
+		{
+			name:   "every repository",
+			target: WaitTarget{},
+			want:   WaitStatus{Total: 3, Succeeded: 2, InProgress: 1},
+		},
+		{
+			name:   "named repository",
+			target: WaitTarget{Repositories: []string{"org/api"}},
+			want:   WaitStatus{Total: 1, Succeeded: 1},
+		},
+		{
+			name:   "named migration",
+			target: WaitTarget{MigrationIDs: []string{"RM_1"}},
+			want:   WaitStatus{Total: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := EvaluateWait(summary, tt.target)
+			status.FailedMigrations = nil
+			if !reflect.DeepEqual(status, tt.want) {
+				t.Errorf("EvaluateWait = %+v, want %+v", status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// timeBound is one end of a time window, either a fixed time, a duration before
+// the current time, or the start of the current day
+type timeBound struct {
+	at         time.Time
+	ago        time.Duration
+	startOfDay bool
+}
+
+// isZero returns true if the bound leaves its end of the window open
+func (b timeBound) isZero() bool {
+	return b.at.IsZero() && b.ago == 0 && !b.startOfDay
+}
+
+// resolve returns the time of the bound relative to now
+func (b timeBound) resolve(now time.Time) time.Time {
+	switch {
+	case b.startOfDay:
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	case b.ago > 0:
+		return now.Add(-b.ago)
+	default:
+		return b.at
+	}
+}
+
+// TimeWindow selects migrations by creation time. Relative bounds, such as the
+// last hour, are resolved against the current time whenever the window is applied,
+// so the window keeps moving while the dashboard is open.
+type TimeWindow struct {
+	// Label describes the window, e.g. in the dashboard title
+	Label string
+	since timeBound
+	until timeBound
+}
+
+// TimeWindowPresets are the windows the dashboard cycles through, starting with
+// every migration
+var TimeWindowPresets = []TimeWindow{
+	{},
+	{Label: "last hour", since: timeBound{ago: time.Hour}},
+	{Label: "today", since: timeBound{startOfDay: true}},
+	{Label: "last 7 days", since: timeBound{ago: 7 * 24 * time.Hour}},
+}
+
+// ParseTimeWindow creates a window from --since and --until values, each of which
+// is a date (2006-01-02), an RFC 3339 timestamp or a duration before now such as
+// 48h. Empty values leave that end of the window open.
+func ParseTimeWindow(since, until string) (TimeWindow, error) {
+	var window TimeWindow
+	var err error
+
+	if window.since, err = parseTimeBound("since", since); err != nil {
+		return TimeWindow{}, err
+	}
+	if window.until, err = parseTimeBound("until", until); err != nil {
+		return TimeWindow{}, err
+	}
+
+	var parts []string
+	if since != "" {
+		parts = append(parts, "since "+since)
+	}
+	if until != "" {
+		parts = append(parts, "until "+until)
+	}
+	window.Label = strings.Join(parts, " ")
+
+	now := time.Now()
+	from, to := window.Bounds(now)
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return TimeWindow{}, fmt.Errorf("--since %s must be before --until %s", since, until)
+	}
+
+	return window, nil
+}
+
+// parseTimeBound parses a flag value into a time bound
+func parseTimeBound(name, value string) (timeBound, error) {
+	if value == "" {
+		return timeBound{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return timeBound{ago: d}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return timeBound{at: t}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return timeBound{at: t}, nil
+	}
+
+	return timeBound{}, fmt.Errorf("invalid --%s value %q (use a date such as 2006-01-02, an RFC 3339 timestamp or a duration such as 48h)", name, value)
+}
+
+// IsZero returns true if the window selects every migration
+func (w TimeWindow) IsZero() bool {
+	return w.since.isZero() && w.until.isZero()
+}
+
+// Bounds returns the start and end of the window relative to now; a zero time
+// leaves that end of the window open
+func (w TimeWindow) Bounds(now time.Time) (since, until time.Time) {
+	return w.since.resolve(now), w.until.resolve(now)
+}
+
+// Contains returns true if every migration selected by other relative to now is
+// also selected by the window
+func (w TimeWindow) Contains(other TimeWindow, now time.Time) bool {
+	since, until := w.Bounds(now)
+	otherSince, otherUntil := other.Bounds(now)
+
+	if !since.IsZero() && (otherSince.IsZero() || otherSince.Before(since)) {
+		return false
+	}
+	if !until.IsZero() && (otherUntil.IsZero() || otherUntil.After(until)) {
+		return false
+	}
+	return true
+}
+
+// Apply sets the window's bounds relative to now on the list options
+func (w TimeWindow) Apply(opts models.ListOptions, now time.Time) models.ListOptions {
+	opts.Since, opts.Until = w.Bounds(now)
+	return opts
+}
+
+// Filter returns the migrations created within the window relative to now
+func (w TimeWindow) Filter(migrations []models.Migration, now time.Time) []models.Migration {
+	since, until := w.Bounds(now)
+	return filterCreatedBetween(migrations, since, until)
+}
+
+// filterCreatedBetween returns the migrations created at or after since and before
+// until; a zero time leaves that side of the window open
+func filterCreatedBetween(migrations []models.Migration, since, until time.Time) []models.Migration {
+	if since.IsZero() && until.IsZero() {
+		return migrations
+	}
+
+	var filtered []models.Migration
+	for _, migration := range migrations {
+		if !since.IsZero() && migration.CreatedAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !migration.CreatedAt.Before(until) {
+			continue
+		}
+		filtered = append(filtered, migration)
+	}
+	return filtered
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindowContains(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	lastHour, today, lastWeek := TimeWindowPresets[1], TimeWindowPresets[2], TimeWindowPresets[3]
+
+	since := func(value string) TimeWindow {
+		window, err := ParseTimeWindow(value, "")
+		if err != nil {
+			t.Fatalf("ParseTimeWindow(%q): %v", value, err)
+		}
+		return window
+	}
+	until := func(value string) TimeWindow {
+		window, err := ParseTimeWindow("", value)
+		if err != nil {
+			t.Fatalf("ParseTimeWindow(until %q): %v", value, err)
+		}
+		return window
+	}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		other  TimeWindow
+		want   bool
+	}{
+		{"every migration contains a preset", TimeWindow{}, lastWeek, true},
+		{"a preset does not contain every migration", lastHour, TimeWindow{}, false},
+		{"a longer window contains a shorter one", since("48h"), today, true},
+		{"a shorter window does not contain a longer one", since("1h"), lastWeek, false},
+		{"a window contains itself", lastHour, lastHour, true},
+		{"today contains the last hour after 1am", today, lastHour, true},
+		{"a window ending in the past contains no preset", until("1h"), lastHour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.other, now); got != tt.want {
+				t.Errorf("Contains = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package state persists dashboard settings between sessions.
+//
+// Settings the user changes interactively, such as the column the migration table
+// is sorted by, are saved to a small JSON file under ~/.gh-migration-monitor/ and
+// restored the next time the dashboard starts.
+package state
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultFileName is the name of the state file inside the configuration directory
+const DefaultFileName = "state.json"
+
+// State holds the dashboard settings kept between sessions
+type State struct {
+	// SortColumn is the column the migration table is sorted by; empty keeps the
+	// migrations grouped by state
+	SortColumn string `json:"sort_column,omitempty"`
+	// SortDescending reverses the order of the sorted column
+	SortDescending bool `json:"sort_descending,omitempty"`
+}
+
+// DefaultPath returns the default state file location, ~/.gh-migration-monitor/state.json
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".gh-migration-monitor", DefaultFileName), nil
+}
+
+// Load reads the state saved at path. A missing file returns the zero state.
+func Load(path string) (State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state to path, creating its directory if needed. The file is
+// replaced atomically, so an interrupted save keeps the previous state.
+func Save(path string, state State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/rivo/tview"
+)
+
+// AbortFunc aborts queued and in-progress migrations
+type AbortFunc func(migrations []models.Migration) []services.AbortResult
+
+// SetAbortFunc sets the function used to abort migrations from the dashboard
+func (d *Dashboard) SetAbortFunc(f AbortFunc) {
+	d.abortFunc = f
+}
+
+// showAbortModal asks for confirmation before aborting the marked or selected
+// queued and in-progress migrations
+func (d *Dashboard) showAbortModal() {
+	if d.app == nil || d.MainGrid == nil || d.abortFunc == nil {
+		return
+	}
+
+	var abortable []models.Migration
+	var skipped []string
+	for _, migration := range d.actionTargets() {
+		if err := services.CheckAbortable(migration); err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		abortable = append(abortable, migration)
+	}
+
+	if len(abortable) == 0 {
+		message := "Select or mark (Space) a queued or in-progress migration to abort"
+		if len(skipped) > 0 {
+			message = skipped[0]
+		}
+		d.StatusBar.SetText(fmt.Sprintf("[red::b]%s", tview.Escape(message)))
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Abort %d migration(s)? This cannot be undone.\n\n", len(abortable))
+	writeMigrationList(&b, abortable)
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "\n%d skipped: %s", len(skipped), skipped[0])
+	}
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"Cancel", "Abort"}).
+		SetDoneFunc(func(_ int, label string) {
+			d.closeModal()
+			if label == "Abort" {
+				d.runAbort(abortable)
+			}
+		})
+
+	d.showModal("abort", modal)
+}
+
+// runAbort aborts the migrations in the background and reports the results
+func (d *Dashboard) runAbort(migrations []models.Migration) {
+	d.StatusBar.SetText(fmt.Sprintf("[yellow::b]Aborting %d migration(s)...", len(migrations)))
+
+	go func() {
+		results := d.abortFunc(migrations)
+
+		d.app.QueueUpdateDraw(func() {
+			if d.isShuttingDown {
+				return
+			}
+			d.AllMigrations.ClearMarks()
+			d.showAbortResults(results)
+		})
+
+		// Show the aborted migrations' new state right away
+		d.handleRefresh()
+	}()
+}
+
+// showAbortResults shows the outcome of an abort in a dialog
+func (d *Dashboard) showAbortResults(results []services.AbortResult) {
+	failed := 0
+	var b strings.Builder
+	for i, result := range results {
+		if i == maxListedMigrations {
+			fmt.Fprintf(&b, "... and %d more\n", len(results)-maxListedMigrations)
+			break
+		}
+
+		if result.Err != nil {
+			fmt.Fprintf(&b, "FAILED %s: %v\n", result.Migration.FullName(), result.Err)
+		} else {
+			fmt.Fprintf(&b, "Aborted %s (%s)\n", result.Migration.FullName(), result.Migration.ID)
+		}
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	title := fmt.Sprintf("Aborted %d of %d migration(s)", len(results)-failed, len(results))
+	d.StatusBar.SetText(fmt.Sprintf("[green::b]%s", title))
+
+	modal := tview.NewModal().
+		SetText(title + "\n\n" + b.String()).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(int, string) {
+			d.closeModal()
+		})
+
+	d.showModal("abort-results", modal)
+}
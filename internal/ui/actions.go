@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/rivo/tview"
+)
+
+// maxListedMigrations limits how many migrations are listed in a dialog
+const maxListedMigrations = 10
+
+// actionTargets returns the marked migrations, or the selected one if none are
+// marked, using their latest known state
+func (d *Dashboard) actionTargets() []models.Migration {
+	marked := d.AllMigrations.MarkedMigrations()
+	if len(marked) == 0 {
+		if migration, ok := d.AllMigrations.SelectedMigration(); ok {
+			marked = append(marked, migration)
+		}
+	}
+
+	current := make(map[string]models.Migration, len(d.allMigrations))
+	for _, migration := range d.allMigrations {
+		current[migration.ID] = migration
+	}
+
+	targets := make([]models.Migration, 0, len(marked))
+	for _, migration := range marked {
+		if latest, ok := current[migration.ID]; ok {
+			targets = append(targets, latest)
+		}
+	}
+	return targets
+}
+
+// writeMigrationList writes one line per migration, up to maxListedMigrations
+func writeMigrationList(b *strings.Builder, migrations []models.Migration) {
+	for i, migration := range migrations {
+		if i == maxListedMigrations {
+			fmt.Fprintf(b, "... and %d more\n", len(migrations)-maxListedMigrations)
+			return
+		}
+		fmt.Fprintf(b, "%s (%s)\n", migration.FullName(), migration.ID)
+	}
+}
+
+// showModal shows a dialog over the main view
+func (d *Dashboard) showModal(name string, modal *tview.Modal) {
+	modal.SetBorderColor(tcell.ColorTeal)
+
+	pages := tview.NewPages().
+		AddPage("main", d.MainGrid, true, true).
+		AddPage(name, modal, true, true)
+
+	d.app.SetRoot(pages, true)
+	d.app.SetFocus(modal)
+}
+
+// closeModal hides a dialog and returns to the main view
+func (d *Dashboard) closeModal() {
+	if d.app == nil || d.MainGrid == nil {
+		return
+	}
+
+	d.app.SetRoot(d.MainGrid, true)
+	d.SetupKeyboardNavigation(d.app, d.MainGrid)
+	d.app.SetFocus(d.AllMigrations.Table)
+}
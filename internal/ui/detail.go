@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/rivo/tview"
+)
+
+// MigrationDetail displays every field of a single migration
+type MigrationDetail struct {
+	*tview.Flex
+	content   *tview.TextView
+	footer    *tview.TextView
+	migration models.Migration
+}
+
+// NewMigrationDetail creates a new migration detail panel
+func NewMigrationDetail() *MigrationDetail {
+	content := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true).
+		SetScrollable(true)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(content, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	flex.SetBorder(true).
+		SetBorderColor(tcell.ColorTeal).
+		SetTitleAlign(tview.AlignLeft).
+		SetTitle(" Migration Details ")
+
+	md := &MigrationDetail{
+		Flex:    flex,
+		content: content,
+		footer:  footer,
+	}
+	md.SetMessage("")
+
+	return md
+}
+
+// SetMigration renders the given migration in the panel
+func (md *MigrationDetail) SetMigration(migration models.Migration) {
+	md.migration = migration
+	md.SetTitle(fmt.Sprintf(" Migration Details - %s ", tview.Escape(migration.RepositoryName)))
+
+	var b strings.Builder
+	writeField := func(label, value string) {
+		if value == "" {
+			value = "[grey::]Not available[-::]"
+		} else {
+			value = tview.Escape(value)
+		}
+		fmt.Fprintf(&b, "[yellow::b]%-19s[-::-] %s\n", label+":", value)
+	}
+
+	writeField("Organization", migration.Organization)
+	writeField("Repository Name", migration.RepositoryName)
+	writeField("Migration ID", migration.ID)
+	fmt.Fprintf(&b, "[yellow::b]%-19s[-::-] [%s::b]%s[-::-]\n", "Status:", stateColorName(migration.State), tview.Escape(string(migration.State)))
+	writeField("Created At", formatTime(migration))
+	writeField("Database ID", migration.DatabaseID)
+	writeField("Source URL", migration.SourceURL)
+	writeField("Migration Source", formatMigrationSource(migration.MigrationSource))
+	writeField("Warnings", fmt.Sprintf("%d", migration.WarningsCount))
+	writeField("Continue On Error", fmt.Sprintf("%t", migration.ContinueOnError))
+	writeField("Migration Log URL", migration.MigrationLogURL)
+	if len(migration.ResourceCounts) > 0 {
+		writeField("Resources", formatResourceCounts(migration.ResourceCounts, ": ", ", "))
+	}
+
+	b.WriteString("\n[yellow::b]Failure Reason:[-::-]\n")
+	if migration.FailureReason == "" {
+		b.WriteString("[grey::]None[-::]\n")
+	} else {
+		b.WriteString(tview.Escape(migration.FailureReason))
+		b.WriteString("\n")
+	}
+
+	md.content.SetText(b.String())
+	md.content.ScrollToBeginning()
+}
+
+// formatMigrationSource describes a migration source on a single line
+func formatMigrationSource(source models.MigrationSource) string {
+	var parts []string
+	for _, part := range []string{source.Name, source.Type, source.URL} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Migration returns the migration currently shown in the panel
+func (md *MigrationDetail) Migration() models.Migration {
+	return md.migration
+}
+
+// SetMessage shows feedback next to the key hints in the panel footer
+func (md *MigrationDetail) SetMessage(message string) {
+	hints := "[white::]c[grey::] Copy log URL  [white::]o[grey::] Open log URL  [white::]l[grey::] View log  [white::]Esc[grey::] Close"
+	if message != "" {
+		hints += "  [yellow::b]" + message
+	}
+	md.footer.SetText(hints)
+}
+
+// stateColorName returns the tview color name used for a migration state
+func stateColorName(state models.State) string {
+	switch {
+	case state.IsSucceeded():
+		return "green"
+	case state.IsFailed():
+		return "red"
+	case state.IsInProgress():
+		return "yellow"
+	case state.IsQueued():
+		return "blue"
+	case state.IsNotMigrated():
+		return "grey"
+	default:
+		return "white"
+	}
+}
+
+// showDetailModal displays the detail panel for the selected migration
+func (d *Dashboard) showDetailModal() {
+	if d.app == nil || d.MainGrid == nil {
+		return
+	}
+
+	migration, ok := d.AllMigrations.SelectedMigration()
+	if !ok {
+		return
+	}
+
+	d.Detail.SetMigration(migration)
+	d.Detail.SetMessage("")
+	d.Detail.SetInputCapture(d.handleDetailInput)
+
+	pages := tview.NewPages().
+		AddPage("main", d.MainGrid, true, true).
+		AddPage("detail", d.centerPanel(d.Detail), true, true)
+
+	d.app.SetRoot(pages, true)
+	d.app.SetFocus(d.Detail)
+}
+
+// handleDetailInput handles keyboard input for the detail panel
+func (d *Dashboard) handleDetailInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter {
+		d.closeDetailModal()
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'c':
+		d.copyLogURL()
+		return nil
+	case 'o':
+		d.openLogURL()
+		return nil
+	case 'l':
+		d.showLog(d.Detail.Migration())
+		return nil
+	}
+	return event
+}
+
+// copyLogURL copies the detail panel's migration log URL to the clipboard
+func (d *Dashboard) copyLogURL() {
+	url := d.Detail.Migration().MigrationLogURL
+	if url == "" {
+		d.Detail.SetMessage("No log URL available")
+		return
+	}
+
+	if err := copyToClipboard(url); err != nil {
+		d.Detail.SetMessage(fmt.Sprintf("Copy failed: %v", err))
+		return
+	}
+	d.Detail.SetMessage("Copied log URL to clipboard")
+}
+
+// openLogURL opens the detail panel's migration log URL in the browser
+func (d *Dashboard) openLogURL() {
+	url := d.Detail.Migration().MigrationLogURL
+	if url == "" {
+		d.Detail.SetMessage("No log URL available")
+		return
+	}
+
+	if err := openBrowser(url); err != nil {
+		d.Detail.SetMessage(err.Error())
+		return
+	}
+	d.Detail.SetMessage("Opened log URL in browser")
+}
+
+// centerPanel centers a primitive using most of the available space
+func (d *Dashboard) centerPanel(p tview.Primitive) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, 0, 6, true).
+			AddItem(nil, 0, 1, false), 0, 8, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// closeDetailModal hides the detail panel and returns to main view
+func (d *Dashboard) closeDetailModal() {
+	if d.app == nil || d.MainGrid == nil {
+		return
+	}
+
+	d.Detail.SetInputCapture(nil)
+
+	// Restore main view
+	d.app.SetRoot(d.MainGrid, true)
+	d.SetupKeyboardNavigation(d.app, d.MainGrid)
+	d.app.SetFocus(d.AllMigrations.Table)
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/manifest"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// FilterOption represents different filter options
+type FilterOption string
+
+const (
+	FilterAll        FilterOption = "All"
+	FilterNotStarted FilterOption = "Not Started"
+	FilterQueued     FilterOption = "Queued"
+	FilterInProgress FilterOption = "In Progress"
+	FilterSucceeded  FilterOption = "Succeeded"
+	FilterFailed     FilterOption = "Failed"
+)
+
+// ParseFilterOption converts a command-line status name into a FilterOption
+func ParseFilterOption(value string) (FilterOption, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "all":
+		return FilterAll, nil
+	case "not-started", "not_started", "notstarted":
+		return FilterNotStarted, nil
+	case "queued":
+		return FilterQueued, nil
+	case "in-progress", "in_progress", "inprogress":
+		return FilterInProgress, nil
+	case "succeeded":
+		return FilterSucceeded, nil
+	case "failed":
+		return FilterFailed, nil
+	default:
+		return "", fmt.Errorf("invalid status filter %q (valid: all, not-started, queued, in-progress, succeeded, failed)", value)
+	}
+}
+
+// ListState returns the GEI migration state that selects the filter's migrations
+// when listing them, or an empty state for filters that are not a single GEI state.
+// Queued covers both QUEUED and NOT_STARTED, so it is only filtered after listing.
+func (f FilterOption) ListState() models.State {
+	switch f {
+	case FilterInProgress:
+		return models.StateInProgress
+	case FilterSucceeded:
+		return models.StateSucceeded
+	case FilterFailed:
+		return models.StateFailed
+	default:
+		return ""
+	}
+}
+
+// FilterMigrations returns the migrations matching the status filter and search term
+func FilterMigrations(migrations []models.Migration, filter FilterOption, searchTerm string) []models.Migration {
+	return filterBySearch(filterByStatus(migrations, filter), searchTerm)
+}
+
+// FilterRows returns the manifest rows whose migration, or placeholder for rows
+// without one, matches the status filter and search term
+func FilterRows(rows []manifest.Row, filter FilterOption, searchTerm string) []manifest.Row {
+	var filtered []manifest.Row
+	for _, row := range rows {
+		migration := row.Effective()
+		if len(FilterMigrations([]models.Migration{migration}, filter, searchTerm)) > 0 {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// filterByStatus filters migrations by status
+func filterByStatus(migrations []models.Migration, filter FilterOption) []models.Migration {
+	if filter == FilterAll || filter == "" {
+		return migrations
+	}
+
+	var filtered []models.Migration
+	for _, migration := range migrations {
+		if matchesFilter(migration, filter) {
+			filtered = append(filtered, migration)
+		}
+	}
+	return filtered
+}
+
+// filterByOrganization filters migrations by organization; an empty organization matches all
+func filterByOrganization(migrations []models.Migration, organization string) []models.Migration {
+	if organization == "" {
+		return migrations
+	}
+
+	var filtered []models.Migration
+	for _, migration := range migrations {
+		if migration.Organization == organization {
+			filtered = append(filtered, migration)
+		}
+	}
+	return filtered
+}
+
+// filterBySearch filters migrations by search term
+func filterBySearch(migrations []models.Migration, searchTerm string) []models.Migration {
+	if searchTerm == "" {
+		return migrations
+	}
+
+	var filtered []models.Migration
+	searchLower := strings.ToLower(searchTerm)
+
+	for _, migration := range migrations {
+		if strings.Contains(strings.ToLower(migration.RepositoryName), searchLower) {
+			filtered = append(filtered, migration)
+		}
+	}
+	return filtered
+}
+
+// matchesFilter checks if a migration matches the given status filter
+func matchesFilter(migration models.Migration, filter FilterOption) bool {
+	switch filter {
+	case FilterNotStarted:
+		return migration.State.IsNotMigrated()
+	case FilterQueued:
+		return migration.State.IsQueued()
+	case FilterInProgress:
+		return migration.State.IsInProgress()
+	case FilterSucceeded:
+		return migration.State.IsSucceeded()
+	case FilterFailed:
+		return migration.State.IsFailed()
+	default:
+		return true
+	}
+}
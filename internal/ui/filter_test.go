@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+func TestFilterOptionListState(t *testing.T) {
+	tests := []struct {
+		filter FilterOption
+		want   models.State
+	}{
+		{FilterAll, ""},
+		{FilterNotStarted, ""},
+		{FilterQueued, ""},
+		{FilterInProgress, models.StateInProgress},
+		{FilterSucceeded, models.StateSucceeded},
+		{FilterFailed, models.StateFailed},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.ListState(); got != tt.want {
+			t.Errorf("%s.ListState() = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestListStateSelectsEveryFilteredMigration(t *testing.T) {
+	// Every GEI state a filter matches must be listed by its list state
+	geiStates := []models.State{
+		models.StateQueued, models.StateNotStarted, models.StateInProgress,
+		models.StateSucceeded, models.StateFailed,
+	}
+
+	for _, filter := range []FilterOption{FilterQueued, FilterInProgress, FilterSucceeded, FilterFailed} {
+		listState := filter.ListState()
+		if listState == "" {
+			continue
+		}
+		for _, state := range geiStates {
+			matched := len(FilterMigrations([]models.Migration{{State: state}}, filter, "")) > 0
+			if matched && state != listState {
+				t.Errorf("%s matches %s, but only %s is listed", filter, state, listState)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat represents a non-interactive output format
+type OutputFormat string
+
+const (
+	FormatTable OutputFormat = "table"
+	FormatJSON  OutputFormat = "json"
+	FormatCSV   OutputFormat = "csv"
+	FormatYAML  OutputFormat = "yaml"
+)
+
+// timeLayout is the timestamp layout used in table and CSV output
+const timeLayout = "2006-01-02 15:04:05"
+
+// ParseOutputFormat validates and normalizes an output format name
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch format := OutputFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FormatTable, nil
+	case FormatTable, FormatJSON, FormatCSV, FormatYAML:
+		return format, nil
+	case "yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (valid: table, json, csv, yaml)", value)
+	}
+}
+
+// WriteSummary renders a migration summary to w in the given format. The wide flag
+// adds the source and warning columns to table output; CSV always includes them.
+func WriteSummary(w io.Writer, summary *models.MigrationSummary, format OutputFormat, wide bool) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(summary); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		return writeCSV(w, summary.All())
+	case FormatTable, "":
+		return writeTable(w, summary.All(), wide, len(summary.Organizations()) > 1)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// WriteIDs writes one migration ID per line, used for quiet output
+func WriteIDs(w io.Writer, summary *models.MigrationSummary) error {
+	for _, migration := range summary.All() {
+		if _, err := fmt.Fprintln(w, migration.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSV writes migrations as CSV with a header row
+func writeCSV(w io.Writer, migrations []models.Migration) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"organization", "repository_name", "id", "state", "created_at", "failure_reason", "migration_log_url",
+		"database_id", "source_url", "continue_on_error", "warnings_count",
+		"migration_source_name", "migration_source_type", "migration_source_url",
+		"resource_counts",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		record := []string{
+			migration.Organization,
+			migration.RepositoryName,
+			migration.ID,
+			string(migration.State),
+			formatTime(migration),
+			migration.FailureReason,
+			migration.MigrationLogURL,
+			migration.DatabaseID,
+			migration.SourceURL,
+			strconv.FormatBool(migration.ContinueOnError),
+			strconv.Itoa(migration.WarningsCount),
+			migration.MigrationSource.Name,
+			migration.MigrationSource.Type,
+			migration.MigrationSource.URL,
+			formatResourceCounts(migration.ResourceCounts, "=", ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTable writes migrations as an aligned plain-text table. The organization
+// column is only included when the migrations span several organizations.
+func writeTable(w io.Writer, migrations []models.Migration, wide, showOrganization bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := "REPOSITORY NAME\tMIGRATION ID\tSTATUS\tCREATED AT"
+	if showOrganization {
+		header = "ORGANIZATION\t" + header
+	}
+	if wide {
+		header += "\tSOURCE\tSOURCE URL\tWARNINGS"
+	}
+	fmt.Fprintln(tw, header)
+
+	for _, migration := range migrations {
+		if showOrganization {
+			fmt.Fprintf(tw, "%s\t", migration.Organization)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s",
+			migration.RepositoryName,
+			migration.ID,
+			migration.State,
+			formatTime(migration),
+		)
+		if wide {
+			fmt.Fprintf(tw, "\t%s\t%s\t%d",
+				migration.MigrationSource.Name,
+				migration.SourceURL,
+				migration.WarningsCount,
+			)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+// formatResourceCounts lists resource types by name with their counts, e.g.
+// "project=1;repository=3" with "=" and ";" as separators
+func formatResourceCounts(counts map[string]int, assign, separator string) string {
+	types := make([]string, 0, len(counts))
+	for resourceType := range counts {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+
+	parts := make([]string, 0, len(types))
+	for _, resourceType := range types {
+		parts = append(parts, fmt.Sprintf("%s%s%d", resourceType, assign, counts[resourceType]))
+	}
+	return strings.Join(parts, separator)
+}
+
+// formatTime formats a migration's creation time, matching the dashboard
+func formatTime(migration models.Migration) string {
+	if migration.CreatedAt.IsZero() {
+		return "Unknown"
+	}
+	return migration.CreatedAt.Format(timeLayout)
+}
@@ -0,0 +1,347 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/rivo/tview"
+)
+
+// LogFunc loads a migration's log, downloading it again when refresh is set
+type LogFunc func(migration models.Migration, refresh bool) (string, error)
+
+// LogViewer displays a migration log with warnings and errors highlighted. The
+// log can be scrolled and searched, jumping between matches with n and N.
+type LogViewer struct {
+	*tview.Flex
+	app     *tview.Application
+	content *tview.TextView
+	search  *tview.InputField
+	footer  *tview.TextView
+	lines   []string
+	term    string
+	matches int
+	current int
+	message string
+
+	closeFunc  func()
+	reloadFunc func()
+}
+
+// NewLogViewer creates a new log viewer
+func NewLogViewer(app *tview.Application) *LogViewer {
+	content := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false).
+		SetScrollable(true)
+
+	search := tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldWidth(40)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(content, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	flex.SetBorder(true).
+		SetBorderColor(tcell.ColorTeal).
+		SetTitleAlign(tview.AlignLeft).
+		SetTitle(" Migration Log ")
+
+	lv := &LogViewer{
+		Flex:    flex,
+		app:     app,
+		content: content,
+		search:  search,
+		footer:  footer,
+	}
+
+	content.SetInputCapture(lv.handleInput)
+	search.SetDoneFunc(lv.handleSearchDone)
+	lv.updateFooter()
+
+	return lv
+}
+
+// SetCloseFunc sets the function called when the viewer is closed
+func (lv *LogViewer) SetCloseFunc(f func()) {
+	lv.closeFunc = f
+}
+
+// SetReloadFunc sets the function called when a fresh copy of the log is requested
+func (lv *LogViewer) SetReloadFunc(f func()) {
+	lv.reloadFunc = f
+}
+
+// SetLog shows the given log, keeping the current search term
+func (lv *LogViewer) SetLog(title, log string) {
+	lv.SetTitle(fmt.Sprintf(" %s ", tview.Escape(title)))
+	lv.lines = strings.Split(strings.TrimRight(log, "\n"), "\n")
+	lv.message = ""
+	lv.render()
+	lv.content.ScrollToBeginning()
+}
+
+// SetMessage shows a message, such as download progress or an error, in place of the log
+func (lv *LogViewer) SetMessage(title, message string) {
+	lv.SetTitle(fmt.Sprintf(" %s ", tview.Escape(title)))
+	lv.lines = nil
+	lv.message = message
+	lv.content.SetText(tview.Escape(message))
+	lv.updateFooter()
+}
+
+// Search highlights every occurrence of term, case-insensitively, and scrolls to the first one
+func (lv *LogViewer) Search(term string) {
+	lv.term = term
+	lv.current = 0
+	lv.render()
+}
+
+// render rebuilds the log text with line colors and search matches
+func (lv *LogViewer) render() {
+	if lv.lines == nil {
+		return
+	}
+
+	text, matches := renderLog(lv.lines, lv.term)
+	lv.content.SetText(text)
+	lv.matches = matches
+	if lv.current >= matches {
+		lv.current = 0
+	}
+	lv.highlightCurrent()
+	lv.updateFooter()
+}
+
+// moveMatch moves the current match forward or backward, wrapping around
+func (lv *LogViewer) moveMatch(step int) {
+	if lv.matches == 0 {
+		return
+	}
+	lv.current = (lv.current + step + lv.matches) % lv.matches
+	lv.highlightCurrent()
+	lv.updateFooter()
+}
+
+// highlightCurrent highlights the current match and scrolls it into view
+func (lv *LogViewer) highlightCurrent() {
+	if lv.matches == 0 {
+		lv.content.Highlight()
+		return
+	}
+	lv.content.Highlight(matchRegion(lv.current)).ScrollToHighlight()
+}
+
+// updateFooter shows the keyboard shortcuts and the search position
+func (lv *LogViewer) updateFooter() {
+	text := "[white::]/[grey::] Search  [white::]n[grey::]/[white::]N[grey::] Next/Previous  [white::]g[grey::]/[white::]G[grey::] Top/Bottom  [white::]r[grey::] Download again  [white::]Esc[grey::] Close"
+	if lv.term != "" {
+		if lv.matches == 0 {
+			text += fmt.Sprintf("  [red::b]No matches for %q", tview.Escape(lv.term))
+		} else {
+			text += fmt.Sprintf("  [yellow::b]%d of %d matches for %q", lv.current+1, lv.matches, tview.Escape(lv.term))
+		}
+	}
+	lv.footer.SetText(text)
+}
+
+// handleInput handles keyboard input while the log has focus
+func (lv *LogViewer) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEscape {
+		lv.close()
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'q':
+		lv.close()
+		return nil
+	case '/':
+		lv.showSearch()
+		return nil
+	case 'n':
+		lv.moveMatch(1)
+		return nil
+	case 'N':
+		lv.moveMatch(-1)
+		return nil
+	case 'r':
+		if lv.reloadFunc != nil {
+			lv.reloadFunc()
+		}
+		return nil
+	}
+	return event
+}
+
+// showSearch replaces the footer with the search input
+func (lv *LogViewer) showSearch() {
+	lv.search.SetText(lv.term)
+	lv.RemoveItem(lv.footer)
+	lv.AddItem(lv.search, 1, 0, true)
+	if lv.app != nil {
+		lv.app.SetFocus(lv.search)
+	}
+}
+
+// handleSearchDone applies the search term on Enter and restores the footer
+func (lv *LogViewer) handleSearchDone(key tcell.Key) {
+	if key == tcell.KeyEnter {
+		lv.Search(lv.search.GetText())
+	}
+
+	lv.RemoveItem(lv.search)
+	lv.AddItem(lv.footer, 1, 0, false)
+	if lv.app != nil {
+		lv.app.SetFocus(lv.content)
+	}
+}
+
+// close calls the close function
+func (lv *LogViewer) close() {
+	if lv.closeFunc != nil {
+		lv.closeFunc()
+	}
+}
+
+// renderLog colors error lines red and warning lines yellow, and marks every
+// case-insensitive occurrence of term as a region. It returns the text and the
+// number of matches.
+func renderLog(lines []string, term string) (string, int) {
+	var b strings.Builder
+	matches := 0
+
+	for _, line := range lines {
+		color := logLineColor(line)
+		if color != "" {
+			fmt.Fprintf(&b, "[%s]", color)
+		}
+
+		rest := line
+		for term != "" {
+			i := indexFold(rest, term)
+			if i < 0 {
+				break
+			}
+			b.WriteString(tview.Escape(rest[:i]))
+			fmt.Fprintf(&b, `["%s"]%s[""]`, matchRegion(matches), tview.Escape(rest[i:i+len(term)]))
+			rest = rest[i+len(term):]
+			matches++
+		}
+		b.WriteString(tview.Escape(rest))
+
+		if color != "" {
+			b.WriteString("[-]")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String(), matches
+}
+
+// indexFold returns the index of the first case-insensitive occurrence of substr
+// in s, or -1. Text whose length changes when lowercased is matched exactly.
+func indexFold(s, substr string) int {
+	lower, lowerSubstr := strings.ToLower(s), strings.ToLower(substr)
+	if len(lower) != len(s) || len(lowerSubstr) != len(substr) {
+		return strings.Index(s, substr)
+	}
+	return strings.Index(lower, lowerSubstr)
+}
+
+// logLineColor returns the color for a log line based on its level, or an empty string
+func logLineColor(line string) string {
+	upper := strings.ToUpper(line)
+	switch {
+	case strings.Contains(upper, "ERROR"):
+		return "red"
+	case strings.Contains(upper, "WARN"):
+		return "yellow"
+	default:
+		return ""
+	}
+}
+
+// matchRegion returns the region ID of a search match
+func matchRegion(index int) string {
+	return fmt.Sprintf("match-%d", index)
+}
+
+// SetLogFunc sets the function used to load migration logs from the dashboard
+func (d *Dashboard) SetLogFunc(f LogFunc) {
+	d.logFunc = f
+}
+
+// showSelectedLog shows the log of the selected migration
+func (d *Dashboard) showSelectedLog() {
+	if migration, ok := d.AllMigrations.SelectedMigration(); ok {
+		d.showLog(migration)
+	}
+}
+
+// showLog downloads a migration's log and shows it in the log viewer
+func (d *Dashboard) showLog(migration models.Migration) {
+	if d.app == nil || d.MainGrid == nil || d.logFunc == nil || migration.ID == "" {
+		return
+	}
+
+	title := fmt.Sprintf("Migration Log - %s (%s)", migration.FullName(), migration.ID)
+	viewer := NewLogViewer(d.app)
+	viewer.SetCloseFunc(d.closeModal)
+
+	load := func(refresh bool) {
+		viewer.SetMessage(title, "Downloading log...")
+		go func() {
+			log, err := d.logFunc(migration, refresh)
+			d.app.QueueUpdateDraw(func() {
+				if err != nil {
+					viewer.SetMessage(title, err.Error())
+					return
+				}
+				viewer.SetLog(title, log)
+			})
+		}()
+	}
+	viewer.SetReloadFunc(func() { load(true) })
+
+	pages := tview.NewPages().
+		AddPage("main", d.MainGrid, true, true).
+		AddPage("log", d.centerPanel(viewer), true, true)
+
+	d.app.SetRoot(pages, true)
+	d.app.SetFocus(viewer)
+
+	load(false)
+}
+
+// RunLogViewer shows a log in a full-screen viewer until it is closed
+func RunLogViewer(title, log string, reload func() (string, error)) error {
+	app := tview.NewApplication()
+	viewer := NewLogViewer(app)
+	viewer.SetLog(title, log)
+	viewer.SetCloseFunc(app.Stop)
+	if reload != nil {
+		viewer.SetReloadFunc(func() {
+			viewer.SetMessage(title, "Downloading log...")
+			go func() {
+				log, err := reload()
+				app.QueueUpdateDraw(func() {
+					if err != nil {
+						viewer.SetMessage(title, err.Error())
+						return
+					}
+					viewer.SetLog(title, log)
+				})
+			}()
+		})
+	}
+
+	return app.SetRoot(viewer, true).SetFocus(viewer).Run()
+}
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/manifest"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// reconciliationOutput is the JSON and YAML representation of a reconciliation
+type reconciliationOutput struct {
+	Expected   int                 `json:"expected" yaml:"expected"`
+	NotStarted int                 `json:"not_started" yaml:"not_started"`
+	Unexpected int                 `json:"unexpected" yaml:"unexpected"`
+	Rows       []reconciliationRow `json:"rows" yaml:"rows"`
+}
+
+// reconciliationRow is the JSON and YAML representation of a reconciliation row
+type reconciliationRow struct {
+	Status    string            `json:"status" yaml:"status"`
+	Entry     *manifest.Entry   `json:"entry,omitempty" yaml:"entry,omitempty"`
+	Migration *models.Migration `json:"migration,omitempty" yaml:"migration,omitempty"`
+}
+
+// WriteReconciliation renders the given manifest rows to w in the given format.
+// The totals of the reconciliation are included regardless of which rows are written.
+func WriteReconciliation(w io.Writer, rec *manifest.Reconciliation, rows []manifest.Row, format OutputFormat) error {
+	switch format {
+	case FormatJSON, FormatYAML:
+		output := reconciliationOutput{
+			Expected:   rec.Expected,
+			NotStarted: rec.NotStarted,
+			Unexpected: rec.Unexpected,
+			Rows:       make([]reconciliationRow, 0, len(rows)),
+		}
+		for _, row := range rows {
+			output.Rows = append(output.Rows, reconciliationRow{Status: row.Status(), Entry: row.Entry, Migration: row.Migration})
+		}
+
+		if format == FormatJSON {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(output)
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatCSV:
+		return writeReconciliationCSV(w, rows)
+	case FormatTable, "":
+		return writeReconciliationTable(w, rec, rows)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// WriteRowIDs writes the migration ID of every row that has a migration, used for quiet output
+func WriteRowIDs(w io.Writer, rows []manifest.Row) error {
+	for _, row := range rows {
+		if row.Migration == nil {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, row.Migration.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeReconciliationCSV writes manifest rows as CSV with a header row
+func writeReconciliationCSV(w io.Writer, rows []manifest.Row) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"manifest_line", "source", "status", "organization", "repository_name", "id", "state", "created_at", "failure_reason"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		line, source := "", ""
+		if row.Entry != nil {
+			line = strconv.Itoa(row.Entry.Line)
+			source = row.Entry.Source
+		}
+
+		migration := row.Effective()
+		createdAt := ""
+		if row.Migration != nil {
+			createdAt = formatTime(migration)
+		}
+
+		record := []string{
+			line,
+			source,
+			row.Status(),
+			migration.Organization,
+			migration.RepositoryName,
+			migration.ID,
+			string(migration.State),
+			createdAt,
+			migration.FailureReason,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeReconciliationTable writes manifest rows as an aligned plain-text table followed by the totals
+func writeReconciliationTable(w io.Writer, rec *manifest.Reconciliation, rows []manifest.Row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LINE\tORGANIZATION\tREPOSITORY NAME\tSOURCE\tMIGRATION ID\tSTATUS\tCREATED AT")
+	for _, row := range rows {
+		line, source := "-", "-"
+		if row.Entry != nil {
+			line = strconv.Itoa(row.Entry.Line)
+			if row.Entry.Source != "" {
+				source = row.Entry.Source
+			}
+		}
+
+		migration := row.Effective()
+		status := string(migration.State)
+		if row.Entry == nil {
+			status += " (unexpected)"
+		}
+
+		id, createdAt := "-", "-"
+		if row.Migration != nil {
+			id = migration.ID
+			createdAt = formatTime(migration)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			line,
+			valueOrDash(migration.Organization),
+			migration.RepositoryName,
+			source,
+			id,
+			status,
+			createdAt,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d expected, %d with a migration, %d not started, %d unexpected\n",
+		rec.Expected, rec.Expected-rec.NotStarted, rec.NotStarted, rec.Unexpected)
+	return err
+}
+
+// valueOrDash returns the value, or a dash if it is empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
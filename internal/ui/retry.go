@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
+	"github.com/rivo/tview"
+)
+
+// RetryFunc retries failed migrations, or only checks them when dryRun is set
+type RetryFunc func(migrations []models.Migration, dryRun bool) []services.RetryResult
+
+// SetRetryFunc sets the function used to retry failed migrations from the dashboard
+func (d *Dashboard) SetRetryFunc(f RetryFunc) {
+	d.retryFunc = f
+}
+
+// showRetryModal asks for confirmation before retrying the marked or selected failed migrations
+func (d *Dashboard) showRetryModal() {
+	if d.app == nil || d.MainGrid == nil || d.retryFunc == nil {
+		return
+	}
+
+	var retryable []models.Migration
+	var skipped []string
+	for _, migration := range d.actionTargets() {
+		if err := services.CheckRetryable(migration, d.allMigrations); err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		retryable = append(retryable, migration)
+	}
+
+	if len(retryable) == 0 {
+		message := "Select or mark (Space) a failed migration to retry"
+		if len(skipped) > 0 {
+			message = skipped[0]
+		}
+		d.StatusBar.SetText(fmt.Sprintf("[red::b]%s", tview.Escape(message)))
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Retry %d failed migration(s)?\n\n", len(retryable))
+	writeMigrationList(&b, retryable)
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "\n%d skipped: %s", len(skipped), skipped[0])
+	}
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"Retry", "Dry run", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			d.closeModal()
+			switch label {
+			case "Retry":
+				d.runRetry(retryable, false)
+			case "Dry run":
+				d.runRetry(retryable, true)
+			}
+		})
+
+	d.showModal("retry", modal)
+}
+
+// runRetry retries the migrations in the background and reports the results
+func (d *Dashboard) runRetry(migrations []models.Migration, dryRun bool) {
+	if dryRun {
+		d.StatusBar.SetText("[yellow::b]Checking migrations...")
+	} else {
+		d.StatusBar.SetText(fmt.Sprintf("[yellow::b]Retrying %d migration(s)...", len(migrations)))
+	}
+
+	go func() {
+		results := d.retryFunc(migrations, dryRun)
+
+		d.app.QueueUpdateDraw(func() {
+			if d.isShuttingDown {
+				return
+			}
+			if !dryRun {
+				d.AllMigrations.ClearMarks()
+			}
+			d.showRetryResults(results, dryRun)
+		})
+
+		// Show the new migrations right away
+		if !dryRun {
+			d.handleRefresh()
+		}
+	}()
+}
+
+// showRetryResults shows the outcome of a retry in a dialog
+func (d *Dashboard) showRetryResults(results []services.RetryResult, dryRun bool) {
+	failed := 0
+	var b strings.Builder
+	for i, result := range results {
+		if i == maxListedMigrations {
+			fmt.Fprintf(&b, "... and %d more\n", len(results)-maxListedMigrations)
+			break
+		}
+
+		label := result.Original.FullName()
+		switch {
+		case result.Err != nil:
+			fmt.Fprintf(&b, "FAILED %s: %v\n", label, result.Err)
+		case dryRun:
+			fmt.Fprintf(&b, "Would retry %s from %s\n", label, result.Original.SourceURL)
+		default:
+			fmt.Fprintf(&b, "Started %s: %s\n", label, result.Migration.ID)
+		}
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	title := fmt.Sprintf("Retried %d of %d migration(s)", len(results)-failed, len(results))
+	if dryRun {
+		title = fmt.Sprintf("Dry run: %d of %d migration(s) can be retried", len(results)-failed, len(results))
+	}
+	d.StatusBar.SetText(fmt.Sprintf("[green::b]%s", title))
+
+	modal := tview.NewModal().
+		SetText(title + "\n\n" + b.String()).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(int, string) {
+			d.closeModal()
+		})
+
+	d.showModal("retry-results", modal)
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+)
+
+// SortColumn identifies a migration table column the rows can be sorted by
+type SortColumn string
+
+const (
+	// SortNone keeps the migrations grouped by state, as they are listed
+	SortNone       SortColumn = ""
+	SortRepository SortColumn = "repository"
+	SortID         SortColumn = "id"
+	SortStatus     SortColumn = "status"
+	SortCreatedAt  SortColumn = "created_at"
+	SortDuration   SortColumn = "duration"
+)
+
+// sortKeys maps the number keys of the dashboard to the columns they sort by
+var sortKeys = map[rune]SortColumn{
+	'0': SortNone,
+	'1': SortRepository,
+	'2': SortID,
+	'3': SortStatus,
+	'4': SortCreatedAt,
+	'5': SortDuration,
+}
+
+// ParseSortColumn validates a sort column, e.g. one read from a previous session
+func ParseSortColumn(value string) (SortColumn, error) {
+	switch column := SortColumn(value); column {
+	case SortNone, SortRepository, SortID, SortStatus, SortCreatedAt, SortDuration:
+		return column, nil
+	default:
+		return SortNone, fmt.Errorf("invalid sort column %q", value)
+	}
+}
+
+// TableSort is the column the migration table is sorted by and its direction
+type TableSort struct {
+	Column     SortColumn
+	Descending bool
+}
+
+// toggle returns the sort after selecting column: selecting the sorted column
+// again reverses its direction, while a new column starts ascending
+func (s TableSort) toggle(column SortColumn) TableSort {
+	if column == SortNone {
+		return TableSort{}
+	}
+	if s.Column == column {
+		return TableSort{Column: column, Descending: !s.Descending}
+	}
+	return TableSort{Column: column}
+}
+
+// indicator returns the arrow shown in the header of the sorted column
+func (s TableSort) indicator(column SortColumn) string {
+	if s.Column == SortNone || s.Column != column {
+		return ""
+	}
+	if s.Descending {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// FinishedAtFunc returns when a finished migration was first seen finished, if known
+type FinishedAtFunc func(models.Migration) (time.Time, bool)
+
+// migrationDuration returns how long a migration has been running, or how long it
+// ran once it finished. It is unknown for migrations that have not started and
+// for finished migrations whose end was never observed.
+func migrationDuration(migration models.Migration, finishedAt FinishedAtFunc, now time.Time) (time.Duration, bool) {
+	if migration.CreatedAt.IsZero() || migration.State.IsNotMigrated() {
+		return 0, false
+	}
+
+	end := now
+	if migration.State.IsSucceeded() || migration.State.IsFailed() {
+		if finishedAt == nil {
+			return 0, false
+		}
+		var ok bool
+		if end, ok = finishedAt(migration); !ok {
+			return 0, false
+		}
+	}
+
+	return max(end.Sub(migration.CreatedAt), 0), true
+}
+
+// formatDuration formats a migration duration with its two largest units
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// sortMigrations returns a copy of the migrations ordered by the sort. Migrations
+// that compare equal keep their order, and migrations without a duration are
+// listed last in either direction.
+func sortMigrations(migrations []models.Migration, tableSort TableSort, finishedAt FinishedAtFunc, now time.Time) []models.Migration {
+	if tableSort.Column == SortNone {
+		return migrations
+	}
+
+	sorted := append([]models.Migration(nil), migrations...)
+
+	var durations map[int]time.Duration
+	if tableSort.Column == SortDuration {
+		durations = make(map[int]time.Duration, len(sorted))
+		for i, migration := range sorted {
+			if d, ok := migrationDuration(migration, finishedAt, now); ok {
+				durations[i] = d
+			}
+		}
+	}
+
+	// Sort indexes so durations stay attached to their migrations
+	indexes := make([]int, len(sorted))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := indexes[i], indexes[j]
+
+		var c int
+		if tableSort.Column == SortDuration {
+			da, aok := durations[a]
+			db, bok := durations[b]
+			if aok != bok {
+				return aok
+			}
+			c = cmp.Compare(da, db)
+		} else {
+			c = compareMigrations(sorted[a], sorted[b], tableSort.Column)
+		}
+
+		if tableSort.Descending {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	result := make([]models.Migration, len(sorted))
+	for i, index := range indexes {
+		result[i] = sorted[index]
+	}
+	return result
+}
+
+// compareMigrations compares two migrations by a column, returning a negative
+// number when a comes first in ascending order
+func compareMigrations(a, b models.Migration, column SortColumn) int {
+	switch column {
+	case SortRepository:
+		if c := strings.Compare(strings.ToLower(a.RepositoryName), strings.ToLower(b.RepositoryName)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Organization, b.Organization)
+	case SortID:
+		return compareIDs(a.ID, b.ID)
+	case SortStatus:
+		if c := stateRank(a.State) - stateRank(b.State); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.State), string(b.State))
+	case SortCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	default:
+		return 0
+	}
+}
+
+// compareIDs compares migration IDs, numerically for legacy migration IDs
+func compareIDs(a, b string) int {
+	na, aErr := strconv.ParseInt(a, 10, 64)
+	nb, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(na, nb)
+	}
+	return strings.Compare(a, b)
+}
+
+// stateRank orders states the way the dashboard groups them: not started, queued,
+// in progress, succeeded, failed
+func stateRank(state models.State) int {
+	switch {
+	case state.IsNotMigrated():
+		return 0
+	case state.IsQueued():
+		return 1
+	case state.IsInProgress():
+		return 2
+	case state.IsSucceeded():
+		return 3
+	case state.IsFailed():
+		return 4
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/rivo/tview"
+)
+
+// progressBarWidth is the number of characters in the summary's progress bar
+const progressBarWidth = 40
+
+// SummaryPanel shows how many of the displayed migrations are in each state, how
+// many have finished and how the counts changed since the previous refresh
+type SummaryPanel struct {
+	*tview.TextView
+}
+
+// NewSummaryPanel creates an empty summary panel
+func NewSummaryPanel() *SummaryPanel {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("")
+	view.SetBorder(true).
+		SetBorderColor(tcell.ColorTeal).
+		SetTitleAlign(tview.AlignLeft).
+		SetTitle(" Summary ")
+
+	return &SummaryPanel{TextView: view}
+}
+
+// Update shows the counts of current. Each count is followed by its change since
+// previous, which is nil before the second refresh.
+func (sp *SummaryPanel) Update(current, previous *models.MigrationSummary) {
+	sp.SetText(formatSummary(current, previous))
+}
+
+// formatSummary formats the per-state counts and the progress bar of the summary panel
+func formatSummary(current, previous *models.MigrationSummary) string {
+	type stateCount struct {
+		label    string
+		color    string
+		count    int
+		previous int
+	}
+
+	counts := []stateCount{
+		{"Queued", "blue", len(current.Queued), 0},
+		{"In Progress", "yellow", len(current.InProgress), 0},
+		{"Succeeded", "green", len(current.Succeeded), 0},
+		{"Failed", "red", len(current.Failed), 0},
+	}
+	if previous != nil {
+		counts[0].previous = len(previous.Queued)
+		counts[1].previous = len(previous.InProgress)
+		counts[2].previous = len(previous.Succeeded)
+		counts[3].previous = len(previous.Failed)
+	}
+
+	// Repositories of a manifest that have not started yet
+	if len(current.NotStarted) > 0 || (previous != nil && len(previous.NotStarted) > 0) {
+		notStarted := stateCount{"Not Started", "grey", len(current.NotStarted), 0}
+		if previous != nil {
+			notStarted.previous = len(previous.NotStarted)
+		}
+		counts = append([]stateCount{notStarted}, counts...)
+	}
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "[white::b]%d[-::-] total", current.Total())
+	if previous != nil {
+		line.WriteString(formatDelta(current.Total() - previous.Total()))
+	}
+	for _, state := range counts {
+		fmt.Fprintf(&line, "   [%s::b]%s[-::-] %d", state.color, state.label, state.count)
+		if previous != nil {
+			line.WriteString(formatDelta(state.count - state.previous))
+		}
+	}
+
+	finished := len(current.Succeeded) + len(current.Failed)
+	percent := 0
+	if current.Total() > 0 {
+		percent = finished * 100 / current.Total()
+	}
+
+	return fmt.Sprintf("%s\n%s [white::b]%d%%[-::-] complete (%d of %d finished)",
+		line.String(), progressBar(len(current.Succeeded), len(current.Failed), current.Total(), progressBarWidth),
+		percent, finished, current.Total())
+}
+
+// formatDelta formats the change of a count since the previous refresh, or
+// nothing when it did not change
+func formatDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf(" [aqua::](+%d)[-::-]", delta)
+	case delta < 0:
+		return fmt.Sprintf(" [aqua::](%d)[-::-]", delta)
+	default:
+		return ""
+	}
+}
+
+// progressBar draws a bar of width characters with the share of succeeded
+// migrations in green, failed ones in red and unfinished ones in grey
+func progressBar(succeeded, failed, total, width int) string {
+	if total == 0 {
+		return "[grey::]" + strings.Repeat("░", width) + "[-::-]"
+	}
+
+	succeededWidth := succeeded * width / total
+	failedWidth := (succeeded+failed)*width/total - succeededWidth
+
+	return "[green::]" + strings.Repeat("█", succeededWidth) +
+		"[red::]" + strings.Repeat("█", failedWidth) +
+		"[grey::]" + strings.Repeat("░", width-succeededWidth-failedWidth) + "[-::-]"
+}
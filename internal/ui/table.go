@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/mona-actions/gh-migration-monitor/internal/models"
@@ -11,26 +13,45 @@ import (
 // MigrationTable represents a table for displaying migrations
 type MigrationTable struct {
 	*tview.Table
-	title string
+	title            string
+	migrations       []models.Migration
+	listed           []models.Migration
+	wide             bool
+	showOrganization bool
+	isUnexpected     func(models.Migration) bool
+	finishedAt       FinishedAtFunc
+	sort             TableSort
+	marked           map[string]models.Migration
+}
+
+// tableColumn is a column of the migration table and the column it sorts by
+type tableColumn struct {
+	header string
+	sortBy SortColumn
 }
 
 // NewMigrationTable creates a new migration table
 func NewMigrationTable(title string) *MigrationTable {
-	table := tview.NewTable().SetBorders(false)
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
 	table.SetBorder(true).
 		SetBorderColor(tcell.ColorTeal).
 		SetTitleAlign(tview.AlignLeft).
 		SetTitle(title)
 
 	return &MigrationTable{
-		Table: table,
-		title: title,
+		Table:  table,
+		title:  title,
+		marked: make(map[string]models.Migration),
 	}
 }
 
 // UpdateData updates the table with new migration data
 func (mt *MigrationTable) UpdateData(migrations []models.Migration) {
 	mt.Clear()
+	mt.migrations = migrations
 
 	// Add headers
 	mt.SetCell(0, 0, tview.NewTableCell("Repository Name").SetExpansion(1))
@@ -42,62 +63,233 @@ func (mt *MigrationTable) UpdateData(migrations []models.Migration) {
 		mt.SetCell(row, 0, tview.NewTableCell(migration.RepositoryName).SetExpansion(1))
 
 		// Format the created at time
-		formattedTime := migration.CreatedAt.Format("2006-01-02 15:04:05")
-		if migration.CreatedAt.IsZero() {
-			formattedTime = "Unknown"
-		}
+		formattedTime := formatTime(migration)
 		mt.SetCell(row, 1, tview.NewTableCell(formattedTime).SetExpansion(1))
 	}
 }
 
-// UpdateDataWithStatus updates the table with migration data including status information
+// UpdateDataWithStatus updates the table with migration data including status
+// information, ordered by the table's sort
 func (mt *MigrationTable) UpdateDataWithStatus(migrations []models.Migration) {
+	now := time.Now()
+
 	mt.Clear()
+	mt.listed = migrations
+	mt.migrations = sortMigrations(migrations, mt.sort, mt.finishedAt, now)
 
-	// Add headers
-	mt.SetCell(0, 0, tview.NewTableCell("Repository Name").SetExpansion(1))
-	mt.SetCell(0, 1, tview.NewTableCell("Migration ID").SetExpansion(1))
-	mt.SetCell(0, 2, tview.NewTableCell("Status").SetExpansion(1))
-	mt.SetCell(0, 3, tview.NewTableCell("Created At").SetExpansion(1))
+	// Add headers, marking the sorted column
+	var columns []tableColumn
+	if mt.showOrganization {
+		columns = append(columns, tableColumn{header: "Organization"})
+	}
+	columns = append(columns,
+		tableColumn{header: "Repository Name", sortBy: SortRepository},
+		tableColumn{header: "Migration ID", sortBy: SortID},
+		tableColumn{header: "Status", sortBy: SortStatus},
+		tableColumn{header: "Created At", sortBy: SortCreatedAt},
+		tableColumn{header: "Duration", sortBy: SortDuration},
+	)
+	if mt.wide {
+		columns = append(columns, tableColumn{header: "Source"}, tableColumn{header: "Source URL"}, tableColumn{header: "Warnings"})
+	}
+	for col, column := range columns {
+		mt.SetCell(0, col, headerCell(column.header+mt.sort.indicator(column.sortBy)))
+	}
 
 	// Add migration data
-	for i, migration := range migrations {
-		row := i + 1
+	for i, migration := range mt.migrations {
+		for col, cell := range mt.rowCells(migration, now) {
+			mt.SetCell(i+1, col, cell)
+		}
+	}
 
-		// Repository Name column
-		mt.SetCell(row, 0, tview.NewTableCell(migration.RepositoryName).SetExpansion(1))
+	// Keep the selection within the new set of rows
+	if selected, _ := mt.GetSelection(); selected > len(migrations) {
+		mt.Select(len(migrations), 0)
+	} else if selected == 0 && len(migrations) > 0 {
+		mt.Select(1, 0)
+	}
+}
+
+// rowCells creates the cells of a migration's row, matching the headers in UpdateDataWithStatus
+func (mt *MigrationTable) rowCells(migration models.Migration, now time.Time) []*tview.TableCell {
+	var cells []*tview.TableCell
 
-		// Migration ID column
-		mt.SetCell(row, 1, tview.NewTableCell(migration.ID).SetExpansion(1))
-
-		// Add status with color coding
-		status := string(migration.State)
-		statusCell := tview.NewTableCell(status).SetExpansion(1)
-
-		// Color code the status
-		switch {
-		case migration.State.IsSucceeded():
-			statusCell.SetTextColor(tcell.ColorGreen)
-		case migration.State.IsFailed():
-			statusCell.SetTextColor(tcell.ColorRed)
-		case migration.State.IsInProgress():
-			statusCell.SetTextColor(tcell.ColorYellow)
-		case migration.State.IsQueued():
-			statusCell.SetTextColor(tcell.ColorBlue)
-		default:
-			statusCell.SetTextColor(tcell.ColorWhite)
+	// Organization column, only shown when monitoring several organizations
+	if mt.showOrganization {
+		cells = append(cells, tview.NewTableCell(migration.Organization).SetExpansion(1))
+	}
+
+	// Repository Name column, flagging migrations that are not in the manifest
+	repositoryCell := tview.NewTableCell(migration.RepositoryName).SetExpansion(1)
+	if mt.isUnexpected != nil && mt.isUnexpected(migration) {
+		repositoryCell.SetText("⚠ " + migration.RepositoryName).SetTextColor(tcell.ColorFuchsia)
+	}
+	if _, marked := mt.marked[migration.ID]; marked && migration.ID != "" {
+		repositoryCell.SetText("● " + repositoryCell.Text).SetAttributes(tcell.AttrBold)
+	}
+
+	// Migration ID column
+	cells = append(cells, repositoryCell, tview.NewTableCell(migration.ID).SetExpansion(1))
+
+	// Add status with color coding
+	status := string(migration.State)
+	statusCell := tview.NewTableCell(status).SetExpansion(1)
+
+	// Color code the status
+	switch {
+	case migration.State.IsSucceeded():
+		statusCell.SetTextColor(tcell.ColorGreen)
+	case migration.State.IsFailed():
+		statusCell.SetTextColor(tcell.ColorRed)
+	case migration.State.IsInProgress():
+		statusCell.SetTextColor(tcell.ColorYellow)
+	case migration.State.IsQueued():
+		statusCell.SetTextColor(tcell.ColorBlue)
+	case migration.State.IsNotMigrated():
+		statusCell.SetTextColor(tcell.ColorGrey)
+	default:
+		statusCell.SetTextColor(tcell.ColorWhite)
+	}
+	cells = append(cells, statusCell)
+
+	// Format the created at time; repositories that have not started have none
+	formattedTime := formatTime(migration)
+	if migration.State.IsNotMigrated() && migration.CreatedAt.IsZero() {
+		formattedTime = "-"
+	}
+	cells = append(cells, tview.NewTableCell(formattedTime).SetExpansion(1))
+
+	// Time running so far, or until the migration was seen finished
+	formattedDuration := "-"
+	if d, ok := migrationDuration(migration, mt.finishedAt, now); ok {
+		formattedDuration = formatDuration(d)
+	}
+	cells = append(cells, tview.NewTableCell(formattedDuration).SetExpansion(1))
+
+	// Optional source and warning columns
+	if mt.wide {
+		warningsCell := tview.NewTableCell(fmt.Sprintf("%d", migration.WarningsCount)).SetExpansion(1)
+		if migration.WarningsCount > 0 {
+			warningsCell.SetTextColor(tcell.ColorOrange)
 		}
-		mt.SetCell(row, 2, statusCell)
 
-		// Format the created at time
-		formattedTime := migration.CreatedAt.Format("2006-01-02 15:04:05")
-		if migration.CreatedAt.IsZero() {
-			formattedTime = "Unknown"
+		cells = append(cells,
+			tview.NewTableCell(migration.MigrationSource.Name).SetExpansion(1),
+			tview.NewTableCell(migration.SourceURL).SetExpansion(1),
+			warningsCell,
+		)
+	}
+
+	return cells
+}
+
+// headerCell creates a non-selectable header cell
+func headerCell(text string) *tview.TableCell {
+	return tview.NewTableCell(text).
+		SetExpansion(1).
+		SetSelectable(false)
+}
+
+// SetWide shows or hides the optional source and warning columns
+func (mt *MigrationTable) SetWide(wide bool) {
+	mt.wide = wide
+	mt.UpdateDataWithStatus(mt.listed)
+}
+
+// IsWide returns true if the optional source and warning columns are shown
+func (mt *MigrationTable) IsWide() bool {
+	return mt.wide
+}
+
+// SetShowOrganization shows or hides the organization column
+func (mt *MigrationTable) SetShowOrganization(show bool) {
+	if mt.showOrganization == show {
+		return
+	}
+	mt.showOrganization = show
+	mt.UpdateDataWithStatus(mt.listed)
+}
+
+// SetUnexpected sets the function deciding which migrations are flagged as not
+// expected by the manifest; nil flags none
+func (mt *MigrationTable) SetUnexpected(isUnexpected func(models.Migration) bool) {
+	mt.isUnexpected = isUnexpected
+}
+
+// SetFinishedAt sets the function returning when finished migrations were first
+// seen finished, used for their durations; nil leaves them unknown
+func (mt *MigrationTable) SetFinishedAt(finishedAt FinishedAtFunc) {
+	mt.finishedAt = finishedAt
+}
+
+// SetSort orders the rows by a column, keeping the selected migration selected
+func (mt *MigrationTable) SetSort(tableSort TableSort) {
+	selected, hasSelection := mt.SelectedMigration()
+
+	// Clearing the sort restores the order the migrations were listed in
+	mt.sort = tableSort
+	mt.UpdateDataWithStatus(mt.listed)
+
+	if !hasSelection {
+		return
+	}
+	for i, migration := range mt.migrations {
+		if migration.ID == selected.ID && migration.RepositoryName == selected.RepositoryName &&
+			migration.Organization == selected.Organization {
+			mt.Select(i+1, 0)
+			return
 		}
-		mt.SetCell(row, 3, tview.NewTableCell(formattedTime).SetExpansion(1))
 	}
 }
 
+// Sort returns the column the rows are ordered by and its direction
+func (mt *MigrationTable) Sort() TableSort {
+	return mt.sort
+}
+
+// ToggleMark marks or unmarks the migration in the currently selected row
+func (mt *MigrationTable) ToggleMark() {
+	migration, ok := mt.SelectedMigration()
+	if !ok || migration.ID == "" {
+		return
+	}
+
+	if _, marked := mt.marked[migration.ID]; marked {
+		delete(mt.marked, migration.ID)
+	} else {
+		mt.marked[migration.ID] = migration
+	}
+	mt.UpdateDataWithStatus(mt.listed)
+}
+
+// MarkedMigrations returns the marked migrations
+func (mt *MigrationTable) MarkedMigrations() []models.Migration {
+	marked := make([]models.Migration, 0, len(mt.marked))
+	for _, migration := range mt.marked {
+		marked = append(marked, migration)
+	}
+	sort.Slice(marked, func(i, j int) bool {
+		return marked[i].CreatedAt.Before(marked[j].CreatedAt)
+	})
+	return marked
+}
+
+// ClearMarks unmarks every migration
+func (mt *MigrationTable) ClearMarks() {
+	clear(mt.marked)
+	mt.UpdateDataWithStatus(mt.listed)
+}
+
+// SelectedMigration returns the migration in the currently selected row
+func (mt *MigrationTable) SelectedMigration() (models.Migration, bool) {
+	row, _ := mt.GetSelection()
+	if row < 1 || row > len(mt.migrations) {
+		return models.Migration{}, false
+	}
+	return mt.migrations[row-1], true
+}
+
 // GetTitle returns the table title
 func (mt *MigrationTable) GetTitle() string {
 	return mt.title
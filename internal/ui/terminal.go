@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"io"
+	"os"
+)
+
+// terminalWriter writes escape sequences, such as the bell and desktop
+// notifications, to the terminal from the dashboard's event loop. The screen is
+// only drawn from the event loop too, so a sequence never lands in the middle of
+// a frame.
+type terminalWriter struct {
+	dashboard *Dashboard
+	out       io.Writer
+}
+
+// TerminalWriter returns a writer for escape sequences that writes them to standard
+// output between screen updates. Writes are queued, so they never report an error.
+func (d *Dashboard) TerminalWriter() io.Writer {
+	return terminalWriter{dashboard: d, out: os.Stdout}
+}
+
+// Write implements io.Writer
+func (w terminalWriter) Write(p []byte) (int, error) {
+	if w.dashboard.app == nil {
+		return w.out.Write(p)
+	}
+
+	sequence := append([]byte(nil), p...)
+	w.dashboard.app.QueueUpdate(func() {
+		_, _ = w.out.Write(sequence)
+	})
+	return len(p), nil
+}
@@ -3,40 +3,46 @@ package ui
 import (
 	"context"
 	"fmt"
-	"strings"
+	"path/filepath"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mona-actions/gh-migration-monitor/internal/manifest"
 	"github.com/mona-actions/gh-migration-monitor/internal/models"
+	"github.com/mona-actions/gh-migration-monitor/internal/services"
 	"github.com/rivo/tview"
 )
 
-// FilterOption represents different filter options
-type FilterOption string
-
-const (
-	FilterAll        FilterOption = "All"
-	FilterQueued     FilterOption = "Queued"
-	FilterInProgress FilterOption = "In Progress"
-	FilterSucceeded  FilterOption = "Succeeded"
-	FilterFailed     FilterOption = "Failed"
-)
-
 // Dashboard represents the main UI dashboard
 type Dashboard struct {
+	Summary          *SummaryPanel
 	AllMigrations    *MigrationTable
+	Detail           *MigrationDetail
 	CommandBar       *tview.TextView
 	StatusBar        *tview.TextView
 	SearchInput      *tview.InputField
 	MainGrid         *tview.Grid
 	app              *tview.Application
 	refreshFunc      func()
+	retryFunc        RetryFunc
+	abortFunc        AbortFunc
+	logFunc          LogFunc
+	sortFunc         func(TableSort)
 	isRefreshing     bool
 	isShuttingDown   bool
 	currentFilter    FilterOption
 	allMigrations    []models.Migration
-	organizationName string
+	previous         []models.Migration
+	hasPrevious      bool
+	organizations    []string
+	organization     string
 	searchTerm       string
+	timeWindows      []services.TimeWindow
+	timeWindow       int
+	lastChanges      int
+	lastFailures     int
+	manifest         *manifest.Manifest
+	reconciliation   *manifest.Reconciliation
 	refreshingCtx    context.Context
 	refreshingCancel context.CancelFunc
 }
@@ -44,13 +50,16 @@ type Dashboard struct {
 // NewDashboard creates a new UI dashboard
 func NewDashboard() *Dashboard {
 	dashboard := &Dashboard{
+		Summary:       NewSummaryPanel(),
 		AllMigrations: NewMigrationTable("Migration Status"),
+		Detail:        NewMigrationDetail(),
 		CommandBar:    createCommandBar(),
 		StatusBar:     createStatusBar(),
 		isRefreshing:  false,
 		currentFilter: FilterAll,
 		allMigrations: make([]models.Migration, 0),
 		searchTerm:    "",
+		timeWindows:   services.TimeWindowPresets,
 	}
 
 	// Create search input
@@ -63,13 +72,29 @@ func NewDashboard() *Dashboard {
 func createCommandBar() *tview.TextView {
 	commandBar := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText("[yellow::b]Commands: [white::]r[grey::] Refresh  [white::]/ [grey::] Search  [white::]x[grey::] Exit  [yellow::b]Filters: [white::]a[grey::] All  [white::]q[grey::] Queued  [white::]i[grey::] In Progress  [white::]s[grey::] Succeeded  [white::]f[grey::] Failed")
+		SetText(commandBarText(false, false))
 
 	commandBar.SetBorder(false)
 
 	return commandBar
 }
 
+// commandBarText returns the keyboard shortcuts, including the not started filter
+// when a manifest is loaded and the organization filter when several organizations
+// are monitored
+func commandBarText(multipleOrganizations, hasManifest bool) string {
+	text := "[yellow::b]Commands: [white::]r[grey::] Refresh  [white::]/ [grey::] Search  [white::]Enter[grey::] Details  [white::]l[grey::] Log  [white::]w[grey::] Wide  [white::]t[grey::] Time  [white::]Space[grey::] Mark  [white::]R[grey::] Retry  [white::]A[grey::] Abort  [white::]x[grey::] Exit\n[yellow::b]Filters:  [white::]a[grey::] All  "
+	if hasManifest {
+		text += "[white::]n[grey::] Not Started  "
+	}
+	text += "[white::]q[grey::] Queued  [white::]i[grey::] In Progress  [white::]s[grey::] Succeeded  [white::]f[grey::] Failed"
+	if multipleOrganizations {
+		text += "  [white::]o[grey::] Organization"
+	}
+	text += "  [white::]1-5[grey::] Sort"
+	return text
+}
+
 // createSearchInput creates the search input field
 func createSearchInput() *tview.InputField {
 	return tview.NewInputField().
@@ -90,108 +115,154 @@ func createStatusBar() *tview.TextView {
 	return statusBar
 }
 
-// UpdateData updates the table with new migration data
-func (d *Dashboard) UpdateData(summary *models.MigrationSummary, organization string) {
+// UpdateData updates the table with new migration data for the monitored organizations
+func (d *Dashboard) UpdateData(summary *models.MigrationSummary, organizations []string) {
 	if summary == nil {
 		return
 	}
 
-	// Store organization name
-	d.organizationName = organization
+	// Keep the migrations of the previous refresh for the summary's changes
+	d.previous = d.allMigrations
+	d.hasPrevious = d.HasData()
+
+	// Store organization names, dropping an organization filter that no longer applies
+	d.organizations = organizations
+	if !containsString(organizations, d.organization) {
+		d.organization = ""
+	}
+
+	// Only show the organization column and key when it tells rows apart
+	multipleOrganizations := len(organizations) > 1
+	d.AllMigrations.SetShowOrganization(multipleOrganizations)
+	d.CommandBar.SetText(commandBarText(multipleOrganizations, d.manifest != nil))
+
+	// Combine all migrations into a single list and store them, adding the
+	// manifest's repositories that have not been migrated yet
+	d.allMigrations = summary.All()
+	if d.manifest != nil {
+		d.reconciliation = d.manifest.Reconcile(d.allMigrations)
+		d.allMigrations = d.reconciliation.Migrations()
+		d.AllMigrations.SetUnexpected(d.reconciliation.IsUnexpected)
+	}
+
+	// Fall back to the listed window once the selected preset no longer fits in it
+	if !d.timeWindowListed(d.timeWindow, time.Now()) {
+		d.timeWindow = 0
+	}
 
 	// Update table title with organization name and current filter
 	d.updateTitle()
 
-	// Combine all migrations into a single list and store them
-	d.allMigrations = make([]models.Migration, 0)
-	d.allMigrations = append(d.allMigrations, summary.Queued...)
-	d.allMigrations = append(d.allMigrations, summary.InProgress...)
-	d.allMigrations = append(d.allMigrations, summary.Succeeded...)
-	d.allMigrations = append(d.allMigrations, summary.Failed...)
-
 	// Apply current filter
 	d.applyFilter()
 }
 
-// applyFilter filters the migrations based on the current filter setting and search term
-func (d *Dashboard) applyFilter() {
-	if len(d.allMigrations) == 0 {
-		d.AllMigrations.UpdateDataWithStatus([]models.Migration{})
+// SetManifest reconciles the migrations with a manifest of expected repositories
+// on every update
+func (d *Dashboard) SetManifest(m *manifest.Manifest) {
+	d.manifest = m
+}
+
+// SetTimeWindow sets the time window the migrations were listed with, which the
+// time window key cycles back to instead of every migration. Only the presets
+// within this window are offered, as the migrations outside it are not listed.
+func (d *Dashboard) SetTimeWindow(window services.TimeWindow) {
+	if window.IsZero() {
 		return
 	}
+	d.timeWindows = append([]services.TimeWindow{window}, services.TimeWindowPresets[1:]...)
+}
 
-	filteredMigrations := d.filterByStatus()
-	filteredMigrations = d.filterBySearch(filteredMigrations)
+// SetSort orders the table by a column, e.g. the one saved in a previous session
+func (d *Dashboard) SetSort(tableSort TableSort) {
+	d.AllMigrations.SetSort(tableSort)
+}
 
-	d.AllMigrations.UpdateDataWithStatus(filteredMigrations)
+// SetSortFunc sets the function called with the new sort whenever the table is
+// sorted from the keyboard, e.g. to save it for the next session
+func (d *Dashboard) SetSortFunc(f func(TableSort)) {
+	d.sortFunc = f
 }
 
-// filterByStatus filters migrations by status
-func (d *Dashboard) filterByStatus() []models.Migration {
-	if d.currentFilter == FilterAll {
-		return d.allMigrations
-	}
+// SetFinishedAtFunc sets the function returning when finished migrations were
+// first seen finished, used for their durations
+func (d *Dashboard) SetFinishedAtFunc(f FinishedAtFunc) {
+	d.AllMigrations.SetFinishedAt(f)
+}
 
-	var filtered []models.Migration
-	for _, migration := range d.allMigrations {
-		if d.matchesCurrentFilter(migration) {
-			filtered = append(filtered, migration)
-		}
-	}
-	return filtered
+// RefreshDurations redraws the table so the durations of running migrations stay
+// current when a refresh found no changes, which also clears the summary's changes
+func (d *Dashboard) RefreshDurations() {
+	d.previous = d.allMigrations
+	d.applyFilter()
 }
 
-// filterBySearch filters migrations by search term
-func (d *Dashboard) filterBySearch(migrations []models.Migration) []models.Migration {
-	if d.searchTerm == "" {
-		return migrations
-	}
+// HasData returns true once migration data has been loaded into the dashboard
+func (d *Dashboard) HasData() bool {
+	return len(d.organizations) > 0
+}
 
-	var filtered []models.Migration
-	searchLower := strings.ToLower(d.searchTerm)
+// Organizations returns the organizations shown in the dashboard
+func (d *Dashboard) Organizations() []string {
+	return d.organizations
+}
 
-	for _, migration := range migrations {
-		if strings.Contains(strings.ToLower(migration.RepositoryName), searchLower) {
-			filtered = append(filtered, migration)
-		}
+// RecordChanges stores how many changes the latest refresh detected, shown next to the last update time
+func (d *Dashboard) RecordChanges(count int) {
+	d.lastChanges = count
+}
+
+// RecordFailures stores how many organizations could not be refreshed, shown next to the last update time
+func (d *Dashboard) RecordFailures(count int) {
+	d.lastFailures = count
+}
+
+// applyFilter filters the migrations based on the current filter setting, time window
+// and search term, and summarizes the filtered migrations
+func (d *Dashboard) applyFilter() {
+	now := time.Now()
+	filteredMigrations := d.filterMigrations(d.allMigrations, now)
+
+	// Compare with the previous refresh under the same filters
+	var previous *models.MigrationSummary
+	if d.hasPrevious {
+		previous = models.NewMigrationSummary(d.filterMigrations(d.previous, now))
 	}
-	return filtered
-}
-
-// matchesCurrentFilter checks if a migration matches the current status filter
-func (d *Dashboard) matchesCurrentFilter(migration models.Migration) bool {
-	switch d.currentFilter {
-	case FilterQueued:
-		return migration.State.IsQueued()
-	case FilterInProgress:
-		return migration.State.IsInProgress()
-	case FilterSucceeded:
-		return migration.State.IsSucceeded()
-	case FilterFailed:
-		return migration.State.IsFailed()
-	default:
-		return true
+	d.Summary.Update(models.NewMigrationSummary(filteredMigrations), previous)
+
+	d.AllMigrations.UpdateDataWithStatus(filteredMigrations)
+}
+
+// filterMigrations returns the migrations selected by the organization, time
+// window, status filter and search term
+func (d *Dashboard) filterMigrations(migrations []models.Migration, now time.Time) []models.Migration {
+	if len(migrations) == 0 {
+		return []models.Migration{}
 	}
+
+	migrations = d.timeWindows[d.timeWindow].Filter(filterByOrganization(migrations, d.organization), now)
+	return FilterMigrations(migrations, d.currentFilter, d.searchTerm)
 }
 
 // SetupGrid creates and configures the grid layout
 func (d *Dashboard) SetupGrid() *tview.Grid {
 	if d.MainGrid == nil {
 		d.MainGrid = tview.NewGrid().
-			SetRows(0, 1).
+			SetRows(4, 0, 2).
 			SetColumns(0).
 			SetBorders(false)
 
-		// Add the main migration table
-		d.MainGrid.AddItem(d.AllMigrations.Table, 0, 0, 1, 1, 0, 0, true)
+		// Add the summary of the displayed migrations above the main migration table
+		d.MainGrid.AddItem(d.Summary, 0, 0, 1, 1, 0, 0, false)
+		d.MainGrid.AddItem(d.AllMigrations.Table, 1, 0, 1, 1, 0, 0, true)
 
 		// Create a flex layout for the bottom row containing command bar and status bar
 		bottomFlex := tview.NewFlex().
 			AddItem(d.CommandBar, 0, 3, false).
 			AddItem(d.StatusBar, 0, 1, false)
 
-		// Add bottom flex at the bottom with fixed height of 1 row
-		d.MainGrid.AddItem(bottomFlex, 1, 0, 1, 1, 0, 0, false)
+		// Add bottom flex at the bottom with fixed height of 2 rows, commands above filters
+		d.MainGrid.AddItem(bottomFlex, 2, 0, 1, 1, 0, 0, false)
 	}
 
 	return d.MainGrid
@@ -205,6 +276,11 @@ func (d *Dashboard) SetupKeyboardNavigation(app *tview.Application, grid *tview.
 
 // handleKeyInput processes keyboard input for the main dashboard
 func (d *Dashboard) handleKeyInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEnter {
+		d.showDetailModal()
+		return nil
+	}
+
 	switch event.Rune() {
 	case 'x':
 		d.handleExit()
@@ -214,9 +290,33 @@ func (d *Dashboard) handleKeyInput(event *tcell.EventKey) *tcell.EventKey {
 	case '/':
 		d.showSearchModal()
 		return nil
-	case 'a', 'q', 'i', 's', 'f':
+	case 'w':
+		d.AllMigrations.SetWide(!d.AllMigrations.IsWide())
+		return nil
+	case 'a', 'n', 'q', 'i', 's', 'f':
 		d.handleFilterKey(event.Rune())
 		return nil
+	case 'o':
+		d.cycleOrganization()
+		return nil
+	case 't':
+		d.cycleTimeWindow()
+		return nil
+	case '0', '1', '2', '3', '4', '5':
+		d.handleSortKey(event.Rune())
+		return nil
+	case ' ':
+		d.AllMigrations.ToggleMark()
+		return nil
+	case 'R':
+		d.showRetryModal()
+		return nil
+	case 'A':
+		d.showAbortModal()
+		return nil
+	case 'l':
+		d.showSelectedLog()
+		return nil
 	}
 	return event
 }
@@ -253,6 +353,7 @@ func (d *Dashboard) handleRefresh() {
 func (d *Dashboard) handleFilterKey(key rune) {
 	filterMap := map[rune]FilterOption{
 		'a': FilterAll,
+		'n': FilterNotStarted,
 		'q': FilterQueued,
 		'i': FilterInProgress,
 		's': FilterSucceeded,
@@ -264,6 +365,21 @@ func (d *Dashboard) handleFilterKey(key rune) {
 	}
 }
 
+// handleSortKey sorts the table by the column of a number key, reversing the
+// direction when the table is already sorted by it
+func (d *Dashboard) handleSortKey(key rune) {
+	column, exists := sortKeys[key]
+	if !exists {
+		return
+	}
+
+	tableSort := d.AllMigrations.Sort().toggle(column)
+	d.AllMigrations.SetSort(tableSort)
+	if d.sortFunc != nil {
+		d.sortFunc(tableSort)
+	}
+}
+
 // setFilter sets the current filter and updates the display
 func (d *Dashboard) setFilter(filter FilterOption) {
 	d.currentFilter = filter
@@ -273,11 +389,101 @@ func (d *Dashboard) setFilter(filter FilterOption) {
 	d.applyFilter()
 }
 
+// cycleOrganization moves the organization filter to the next monitored organization,
+// wrapping around to all organizations
+func (d *Dashboard) cycleOrganization() {
+	if len(d.organizations) < 2 {
+		return
+	}
+
+	next := ""
+	for i, org := range d.organizations {
+		if org == d.organization {
+			if i+1 < len(d.organizations) {
+				next = d.organizations[i+1]
+			}
+			break
+		}
+	}
+	if d.organization == "" {
+		next = d.organizations[0]
+	}
+
+	d.organization = next
+	d.updateTitle()
+	d.applyFilter()
+}
+
+// cycleTimeWindow moves to the next time window within the listed window,
+// wrapping around to the listed window itself
+func (d *Dashboard) cycleTimeWindow() {
+	now := time.Now()
+	for {
+		d.timeWindow = (d.timeWindow + 1) % len(d.timeWindows)
+		if d.timeWindowListed(d.timeWindow, now) {
+			break
+		}
+	}
+	d.updateTitle()
+	d.applyFilter()
+}
+
+// timeWindowListed returns true if every migration of a time window was listed.
+// Relative windows move, so a preset can stop fitting, e.g. today once the listed
+// last hour started after midnight.
+func (d *Dashboard) timeWindowListed(index int, now time.Time) bool {
+	return index == 0 || d.timeWindows[0].Contains(d.timeWindows[index], now)
+}
+
 // updateTitle updates the table title with organization and current filter
 func (d *Dashboard) updateTitle() {
-	if d.organizationName != "" {
-		d.AllMigrations.SetTitleWithOrganizationAndFilter(d.organizationName, string(d.currentFilter))
+	if len(d.organizations) == 0 {
+		return
+	}
+
+	label := d.organizations[0]
+	if len(d.organizations) > 1 {
+		label = fmt.Sprintf("%d organizations", len(d.organizations))
+		if d.organization != "" {
+			label = fmt.Sprintf("%s (1 of %d organizations)", d.organization, len(d.organizations))
+		}
+	}
+	d.AllMigrations.SetTitleWithOrganizationAndFilter(label, string(d.currentFilter))
+
+	title := d.AllMigrations.GetTitle()
+
+	if window := d.timeWindows[d.timeWindow]; window.Label != "" {
+		title += " - " + window.Label
 	}
+
+	// Aggregate counts across the selected organizations
+	if len(d.organizations) > 1 {
+		summary := models.NewMigrationSummary(filterByOrganization(d.allMigrations, d.organization))
+		title += fmt.Sprintf(" - %d migrations: %d queued, %d in progress, %d succeeded, %d failed",
+			summary.Total()-len(summary.NotStarted),
+			len(summary.Queued), len(summary.InProgress), len(summary.Succeeded), len(summary.Failed))
+	}
+
+	// Manifest reconciliation counts
+	if d.reconciliation != nil {
+		title += fmt.Sprintf(" - %s: %d expected, %d not started, %d unexpected",
+			filepath.Base(d.manifest.Path), d.reconciliation.Expected, d.reconciliation.NotStarted, d.reconciliation.Unexpected)
+	}
+
+	d.AllMigrations.SetTitle(title)
+}
+
+// containsString returns true if value is empty or present in values
+func containsString(values []string, value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // showSearchModal displays the search modal
@@ -372,6 +578,17 @@ func (d *Dashboard) closeSearchModal() {
 	d.app.SetFocus(d.AllMigrations.Table)
 }
 
+// QueueUpdate runs f on the application's event loop and redraws the screen, or
+// runs it right away before the application is set up. The key handlers read the
+// dashboard's data on the event loop, so refreshes must change it through here.
+func (d *Dashboard) QueueUpdate(f func()) {
+	if d.app == nil {
+		f()
+		return
+	}
+	d.app.QueueUpdateDraw(f)
+}
+
 // SetRefreshFunc sets the function to call when refresh is triggered
 func (d *Dashboard) SetRefreshFunc(f func()) {
 	d.refreshFunc = f
@@ -438,7 +655,16 @@ func (d *Dashboard) HideRefreshing() {
 	if !d.isShuttingDown {
 		d.app.QueueUpdateDraw(func() {
 			currentTime := time.Now().Format("15:04:05")
-			d.StatusBar.SetText(fmt.Sprintf("[green::b]Last updated: %s", currentTime))
+			status := fmt.Sprintf("[green::b]Last updated: %s", currentTime)
+			if d.lastChanges == 1 {
+				status += " [yellow::b](1 change)"
+			} else if d.lastChanges > 1 {
+				status += fmt.Sprintf(" [yellow::b](%d changes)", d.lastChanges)
+			}
+			if d.lastFailures > 0 {
+				status += fmt.Sprintf(" [red::b](%d failed)", d.lastFailures)
+			}
+			d.StatusBar.SetText(status)
 		})
 	}
 }